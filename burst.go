@@ -0,0 +1,212 @@
+package fake
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// BurstRandom generates true/false values like Random but bad values come in
+// bursts, like the outages of a real network. It is a two-state Markov chain
+// (the Gilbert–Elliott model without loss in the good state): while good it
+// turns bad with a small probability every sample and while bad it turns good
+// again with a probability of 1 over the mean burst length. The transition
+// probabilities are chosen so that in the long run the same percentage of
+// values is good as with a Random.
+type BurstRandom struct {
+	id        string
+	rnd       source
+	pctGood   float64
+	meanBurst float64
+	toBad     float64
+	toGood    float64
+	first     bool
+	keepStats bool
+	Stats     *RandomStats
+	v         bool
+}
+
+// Next generates the next value.
+func (fb *BurstRandom) Next() {
+	a := fb.rnd.Float64()
+
+	switch {
+	case fb.first:
+		// Start in either state as often as it is seen in the long run
+		fb.first = false
+		fb.v = a < fb.pctGood
+	case fb.v:
+		fb.v = a >= fb.toBad
+	default:
+		fb.v = a < fb.toGood
+	}
+
+	if fb.keepStats {
+		fb.Stats.add(fb.v)
+	}
+}
+
+// Val returns the current value.
+func (fb *BurstRandom) Val() interface{} {
+	return fb.v
+}
+
+// Vals returns the next count of values as an interface{} array.
+func (fb *BurstRandom) Vals(count int) []interface{} {
+	return makeValues(fb, count)
+}
+
+// JSONStats retrieves the current stats as s JSON string.
+func (fb *BurstRandom) JSONStats() string {
+	return fb.Stats.JSON()
+}
+
+// ID returns the unique id.
+func (fb *BurstRandom) ID() string {
+	return fb.id
+}
+
+// PctGood returns the percentage (0 to 1) of values that are "good" in the
+// long run.
+func (fb *BurstRandom) PctGood() float64 {
+	return fb.pctGood
+}
+
+// MeanBurst returns the mean length of a run of "bad" values.
+func (fb *BurstRandom) MeanBurst() float64 {
+	return fb.meanBurst
+}
+
+// burstRandomState is the runtime state of a BurstRandom saved by Snapshot().
+type burstRandomState struct {
+	ID     string      `json:"id"`
+	Source sourceState `json:"source"`
+	V      bool        `json:"v"`
+	Stats  RandomStats `json:"stats"`
+}
+
+// Snapshot saves the runtime state of the burst random, including the
+// position of its random numbers and its statistics, so it can be restored
+// later with Restore().
+func (fb *BurstRandom) Snapshot() ([]byte, error) {
+	return json.Marshal(burstRandomState{ID: fb.id, Source: fb.rnd.state(), V: fb.v, Stats: *fb.Stats})
+}
+
+// Restore restores the runtime state saved by Snapshot(). The burst random
+// must have been created with the same parameters as the one that was saved
+// and will continue with the identical sequence.
+func (fb *BurstRandom) Restore(b []byte) error {
+	var st burstRandomState
+	if err := json.Unmarshal(b, &st); err != nil {
+		return err
+	}
+
+	if st.ID != fb.id {
+		return errors.New("cannot restore a fake burst random with id '" + fb.id + "' from a snapshot of id '" + st.ID + "'")
+	}
+
+	fb.rnd.restore(st.Source)
+	fb.first = false
+	fb.v = st.V
+	*fb.Stats = st.Stats
+	return nil
+}
+
+// Good returns whether the current value is "good".
+func (fb *BurstRandom) Good() bool {
+	return fb.v
+}
+
+// Bad returns whether the current value is "bad".
+func (fb *BurstRandom) Bad() bool {
+	return !fb.v
+}
+
+// Values returns the next count of values as a bool array.
+func (fb *BurstRandom) Values(count int) []bool {
+	out := make([]bool, count)
+	fb.Fill(out)
+	return out
+}
+
+// Step returns the current value and generates the next one.
+func (fb *BurstRandom) Step() bool {
+	out := fb.Good()
+	fb.Next()
+	return out
+}
+
+// Fill writes the next len(dst) values into dst without allocating and
+// returns how many were written.
+func (fb *BurstRandom) Fill(dst []bool) int {
+	for i := range dst {
+		dst[i] = fb.Good()
+		fb.Next()
+	}
+
+	return len(dst)
+}
+
+// burstTransitions returns the probabilities of turning bad while good and
+// of turning good while bad for the long-run percentage of good values and
+// the mean length of a burst of bad values. Invalid values are added to ce.
+func burstTransitions(ce *ConfigError, pctGood float64, meanBurst float64) (float64, float64) {
+	if pctGood < 0 || pctGood > 1 {
+		ce.add("PctGood", pctGood, "must be between 0 and 1")
+		return 0, 0
+	}
+
+	if meanBurst < 1 {
+		ce.add("MeanBurst", meanBurst, "must be at least 1")
+		return 0, 0
+	}
+
+	// Never good again
+	if pctGood == 0 {
+		return 1, 0
+	}
+
+	toGood := 1 / meanBurst
+	toBad := (1 - pctGood) * toGood / pctGood
+	if toBad > 1 {
+		ce.add("MeanBurst", meanBurst, "must be at least "+fmt.Sprintf("%v", (1-pctGood)/pctGood)+" for a PctGood of "+fmt.Sprintf("%v", pctGood))
+		return 0, 0
+	}
+
+	return toBad, toGood
+}
+
+// NewBurstRandom creates a new BurstRandom. A burst random has a unique id, a
+// random seed to ensure consistency when generating random numbers for the
+// same seed, a percentage of "good" samples in the long run, the mean number
+// of "bad" samples in a row and needs to know wheter to keep internal
+// statistics.
+//
+// For example a percentage good of 0.99 with a mean burst of 10 is bad 1% of
+// the time in outages that last 10 samples on average.
+func NewBurstRandom(id string, seed int64, pctGood float64, meanBurst float64, keepStats bool) (*BurstRandom, error) {
+	if id == "" {
+		return nil, errors.New("ID for a fake burst random cannot be blank")
+	}
+
+	ce := &ConfigError{Kind: "burst random", ID: id}
+	toBad, toGood := burstTransitions(ce, pctGood, meanBurst)
+	if err := ce.err(); err != nil {
+		return nil, err
+	}
+
+	r := &BurstRandom{
+		id:        id,
+		rnd:       generateRandom(seed, false),
+		pctGood:   pctGood,
+		meanBurst: meanBurst,
+		toBad:     toBad,
+		toGood:    toGood,
+		first:     true,
+		keepStats: keepStats,
+		Stats:     &RandomStats{ID: id},
+	}
+
+	r.Next()
+	return r, nil
+}
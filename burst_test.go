@@ -0,0 +1,61 @@
+package fake
+
+import (
+	"fmt"
+	"strings"
+)
+
+func ExampleNewBurstRandom() {
+	fb, _ := NewBurstRandom("network", 4, 0.9, 5, true)
+
+	var sb strings.Builder
+	for _, good := range fb.Values(80) {
+		if good {
+			sb.WriteString(".")
+		} else {
+			sb.WriteString("x")
+		}
+	}
+	fmt.Println(sb.String())
+
+	fb.Values(100000)
+	fmt.Printf("%.3f %.2f %v\n", fb.Stats.CRatio, fb.Stats.CMeanBurst, fb.Stats.CMaxBurst)
+	// Output:
+	// ...................x..................xxxx.....xxxx...............xx............
+	// 0.899 4.87 32
+}
+
+// The same percentage of good values but in bursts.
+func ExampleNewBurstRandom_random() {
+	fr, _ := NewRandom("random", 4, 0.9, true)
+	fb, _ := NewBurstRandom("burst", 4, 0.9, 5, true)
+	fr.Values(100000)
+	fb.Values(100000)
+
+	fmt.Printf("random: %.3f good in %v bursts of %.2f\n", fr.Stats.CRatio, fr.Stats.CBursts, fr.Stats.CMeanBurst)
+	fmt.Printf("burst:  %.3f good in %v bursts of %.2f\n", fb.Stats.CRatio, fb.Stats.CBursts, fb.Stats.CMeanBurst)
+	// Output:
+	// random: 0.900 good in 8971 bursts of 1.11
+	// burst:  0.899 good in 2069 bursts of 4.87
+}
+
+func ExampleBurstRandom_Snapshot() {
+	fb1, _ := NewBurstRandom("network", 4, 0.9, 5, true)
+	fb1.Values(50)
+	snapshot, _ := fb1.Snapshot()
+
+	fb2, _ := NewBurstRandom("network", 4, 0.9, 5, true)
+	fb2.Restore(snapshot)
+	fmt.Println(fb2.Values(10))
+	fmt.Println(fb1.Values(10))
+	// Output:
+	// [false true true true true true true true true true]
+	// [false true true true true true true true true true]
+}
+
+func ExampleNewBurstRandom_invalid() {
+	_, err := NewBurstRandom("network", 4, 0.2, 2, true)
+	fmt.Println(err)
+	// Output:
+	// invalid fake burst random with id 'network': MeanBurst must be at least 4 for a PctGood of 0.2 but was '2'
+}
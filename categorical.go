@@ -0,0 +1,429 @@
+package fake
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// Categorical generates one of a fixed set of states, e.g. "UP", "DEGRADED"
+// and "DOWN", HTTP status codes or the states of a job. States are either
+// drawn from a weighted distribution every sample or follow a Markov chain
+// where the next state depends on the current one. A minimum dwell time
+// keeps a state for a number of samples before it can change.
+type Categorical struct {
+	id          string
+	rnd         source
+	states      []string
+	weights     []float64
+	transitions [][]float64
+	minDwell    []int64
+	first       bool
+	state       int
+	dwell       int64
+	keepStats   bool
+	Stats       *CategoricalStats
+}
+
+// CategoricalStats keeps track of various statistics of a Categorical while
+// it's running.
+type CategoricalStats struct {
+
+	// The ID of the Categorical.
+	ID string `json:"id"`
+
+	// Cumulative count of how many times Next() was called.
+	CTotal int64 `json:"cumulativeTotal"`
+
+	// Cumulative count of every state.
+	CCounts map[string]int64 `json:"cumulativeCounts"`
+
+	// Cumulative count of changes from one state (the outer key) to another.
+	CTransitions map[string]map[string]int64 `json:"cumulativeTransitions"`
+
+	// Slot count of how many times Next() was called. This gets reset after every JSON() call.
+	Total int64 `json:"slotTotal"`
+
+	// Slot count of every state. This gets reset after every JSON() call.
+	Counts map[string]int64 `json:"slotCounts"`
+
+	// Slot count of changes from one state (the outer key) to another. This gets reset after every JSON() call.
+	Transitions map[string]map[string]int64 `json:"slotTransitions"`
+
+	// The previous state, to count the next transition from.
+	Last string `json:"last"`
+}
+
+// NewCategoricalStats creates empty statistics.
+func NewCategoricalStats(id string) *CategoricalStats {
+	return &CategoricalStats{
+		ID:           id,
+		CCounts:      map[string]int64{},
+		CTransitions: map[string]map[string]int64{},
+		Counts:       map[string]int64{},
+		Transitions:  map[string]map[string]int64{},
+	}
+}
+
+// Add adds a state to the running tally.
+func (cs *CategoricalStats) Add(v string) {
+	if cs.CTotal > 0 && v != cs.Last {
+		addTransition(cs.CTransitions, cs.Last, v)
+		addTransition(cs.Transitions, cs.Last, v)
+	}
+
+	cs.Last = v
+	cs.CTotal++
+	cs.Total++
+	cs.CCounts[v]++
+	cs.Counts[v]++
+}
+
+// addTransition counts a change from one state to another.
+func addTransition(transitions map[string]map[string]int64, from string, to string) {
+	if transitions[from] == nil {
+		transitions[from] = map[string]int64{}
+	}
+
+	transitions[from][to]++
+}
+
+// JSON returns a summary of the current categorical statistics and resets
+// the slot tally.
+func (cs *CategoricalStats) JSON() string {
+	out, _ := json.Marshal(cs)
+	cs.Total = 0
+	cs.Counts = map[string]int64{}
+	cs.Transitions = map[string]map[string]int64{}
+	return string(out)
+
+}
+
+// Next generates the next state.
+func (fc *Categorical) Next() {
+	// Every sample draws exactly one random number, even when the state
+	// cannot change
+	a := fc.rnd.Float64()
+
+	next := fc.state
+	switch {
+	case fc.first:
+		fc.first = false
+		if fc.transitions == nil {
+			next = pick(fc.weights, a)
+		}
+	case fc.minDwell != nil && fc.dwell < fc.minDwell[fc.state]:
+	case fc.transitions != nil:
+		next = pick(fc.transitions[fc.state], a)
+	default:
+		next = pick(fc.weights, a)
+	}
+
+	if next == fc.state && fc.dwell > 0 {
+		fc.dwell++
+	} else {
+		fc.dwell = 1
+	}
+	fc.state = next
+
+	if fc.keepStats {
+		fc.Stats.Add(fc.states[fc.state])
+	}
+}
+
+// pick returns the index of the weight the random number a in [0.0,1.0)
+// falls into. The weights are cumulative and end with 1.
+func pick(weights []float64, a float64) int {
+	for i, w := range weights {
+		if a < w {
+			return i
+		}
+	}
+
+	return len(weights) - 1
+}
+
+// Val returns the current state.
+func (fc *Categorical) Val() interface{} {
+	return fc.states[fc.state]
+}
+
+// Vals returns the next count of values as an interface{} array.
+func (fc *Categorical) Vals(count int) []interface{} {
+	return makeValues(fc, count)
+}
+
+// JSONStats retrieves the current stats as s JSON string.
+func (fc *Categorical) JSONStats() string {
+	return fc.Stats.JSON()
+}
+
+// ID returns the unique id.
+func (fc *Categorical) ID() string {
+	return fc.id
+}
+
+// State returns the current state.
+func (fc *Categorical) State() string {
+	return fc.states[fc.state]
+}
+
+// Index returns the position of the current state in the states of the
+// config.
+func (fc *Categorical) Index() int {
+	return fc.state
+}
+
+// Dwell returns for how many samples the current state has lasted, including
+// the current one.
+func (fc *Categorical) Dwell() int64 {
+	return fc.dwell
+}
+
+// Values returns the next count of states as a string array.
+func (fc *Categorical) Values(count int) []string {
+	out := make([]string, count)
+	fc.Fill(out)
+	return out
+}
+
+// Step returns the current state and generates the next one.
+func (fc *Categorical) Step() string {
+	out := fc.State()
+	fc.Next()
+	return out
+}
+
+// Fill writes the next len(dst) states into dst without allocating and
+// returns how many were written.
+func (fc *Categorical) Fill(dst []string) int {
+	for i := range dst {
+		dst[i] = fc.State()
+		fc.Next()
+	}
+
+	return len(dst)
+}
+
+// categoricalState is the runtime state of a Categorical saved by Snapshot().
+type categoricalState struct {
+	ID     string           `json:"id"`
+	Source sourceState      `json:"source"`
+	State  int              `json:"state"`
+	Dwell  int64            `json:"dwell"`
+	Stats  CategoricalStats `json:"stats"`
+}
+
+// Snapshot saves the runtime state of the categorical, including the
+// position of its random numbers and its statistics, so it can be restored
+// later with Restore().
+func (fc *Categorical) Snapshot() ([]byte, error) {
+	return json.Marshal(categoricalState{ID: fc.id, Source: fc.rnd.state(), State: fc.state, Dwell: fc.dwell, Stats: *fc.Stats})
+}
+
+// Restore restores the runtime state saved by Snapshot(). The categorical
+// must have been created with the same parameters as the one that was saved
+// and will continue with the identical sequence.
+func (fc *Categorical) Restore(b []byte) error {
+	st := categoricalState{Stats: *NewCategoricalStats(fc.id)}
+	if err := json.Unmarshal(b, &st); err != nil {
+		return err
+	}
+
+	if st.ID != fc.id {
+		return errors.New("cannot restore a fake categorical with id '" + fc.id + "' from a snapshot of id '" + st.ID + "'")
+	}
+
+	if st.State < 0 || st.State >= len(fc.states) {
+		return errors.New("cannot restore a fake categorical with id '" + fc.id + "' from a snapshot of another config")
+	}
+
+	fc.rnd.restore(st.Source)
+	fc.first = false
+	fc.state = st.State
+	fc.dwell = st.Dwell
+	*fc.Stats = st.Stats
+	return nil
+}
+
+// CategoricalConfig holds every parameter of a Categorical. Without weights
+// or transitions every state is equally likely.
+type CategoricalConfig struct {
+	ID     string   `json:"id" yaml:"id"`
+	States []string `json:"states" yaml:"states"`
+
+	// Relative weights of the states, in the same order. Every sample draws a
+	// state from them independently of the previous one.
+	Weights []float64 `json:"weights" yaml:"weights"`
+
+	// Relative weights of moving from a state (the row) to every state (the
+	// column) in the same order as States. Makes the Categorical a Markov
+	// chain, Weights are ignored.
+	Transitions [][]float64 `json:"transitions" yaml:"transitions"`
+
+	// The first state of a Markov chain. Defaults to the first state.
+	Initial string `json:"initial" yaml:"initial"`
+
+	// The minimum number of samples every state lasts, in the same order as
+	// States. Optional.
+	MinDwell []int64 `json:"minDwell" yaml:"minDwell"`
+
+	// A seed to use for the random numbers. Two categoricals with the same
+	// seed (that is not negative) and parameters generate identical states. A
+	// negative seed uses the current time as the seed.
+	Seed int64 `json:"seed" yaml:"seed"`
+
+	KeepStats bool `json:"keepStats" yaml:"keepStats"`
+}
+
+// Validate checks the config for values that would generate nonsense. It
+// returns nil or a *ConfigError listing every invalid field.
+func (cfg CategoricalConfig) Validate() error {
+	ce := &ConfigError{Kind: "categorical", ID: cfg.ID}
+
+	if cfg.ID == "" {
+		ce.add("ID", cfg.ID, "cannot be blank")
+	}
+
+	if len(cfg.States) == 0 {
+		ce.add("States", cfg.States, "must have at least one state")
+	}
+
+	seen := map[string]bool{}
+	for i, s := range cfg.States {
+		if s == "" {
+			ce.add("States["+fmt.Sprintf("%v", i)+"]", s, "cannot be blank")
+		} else if seen[s] {
+			ce.add("States["+fmt.Sprintf("%v", i)+"]", s, "is a duplicate")
+		}
+		seen[s] = true
+	}
+
+	n := len(cfg.States)
+	if cfg.Transitions != nil {
+		if len(cfg.Transitions) != n {
+			ce.add("Transitions", len(cfg.Transitions), "must have a row for each of the "+fmt.Sprintf("%v", n)+" states")
+		}
+
+		for i, row := range cfg.Transitions {
+			checkWeights(ce, "Transitions["+fmt.Sprintf("%v", i)+"]", row, n)
+		}
+	} else if cfg.Weights != nil {
+		checkWeights(ce, "Weights", cfg.Weights, n)
+	}
+
+	if cfg.Initial != "" && !seen[cfg.Initial] {
+		ce.add("Initial", cfg.Initial, "must be one of the states")
+	}
+
+	if cfg.MinDwell != nil {
+		if len(cfg.MinDwell) != n {
+			ce.add("MinDwell", len(cfg.MinDwell), "must have a value for each of the "+fmt.Sprintf("%v", n)+" states")
+		}
+
+		for i, d := range cfg.MinDwell {
+			if d < 0 {
+				ce.add("MinDwell["+fmt.Sprintf("%v", i)+"]", d, "cannot be less than 0")
+			}
+		}
+	}
+
+	return ce.err()
+}
+
+// checkWeights adds an error for every invalid weight of field.
+func checkWeights(ce *ConfigError, field string, weights []float64, n int) {
+	if len(weights) != n {
+		ce.add(field, len(weights), "must have a weight for each of the "+fmt.Sprintf("%v", n)+" states")
+		return
+	}
+
+	positive := false
+	for i, w := range weights {
+		if w < 0 {
+			ce.add(field+"["+fmt.Sprintf("%v", i)+"]", w, "cannot be less than 0")
+		}
+		positive = positive || w > 0
+	}
+
+	if !positive {
+		ce.add(field, weights, "must have at least one weight greater than 0")
+	}
+}
+
+// cumulative turns relative weights into cumulative ones ending with 1. No
+// weights make every state equally likely.
+func cumulative(weights []float64, n int) []float64 {
+	if weights == nil {
+		weights = make([]float64, n)
+		for i := range weights {
+			weights[i] = 1
+		}
+	}
+
+	var sum float64
+	for _, w := range weights {
+		sum += w
+	}
+
+	out := make([]float64, len(weights))
+	var acc float64
+	for i, w := range weights {
+		acc += w
+		out[i] = acc / sum
+	}
+	out[len(out)-1] = 1
+
+	return out
+}
+
+// NewCategorical creates a new Categorical drawing every sample from weighted
+// states. A categorical has a unique id, a random seed to ensure consistency
+// when generating random numbers for the same seed, the states and their
+// relative weights (nil for equal weights) and needs to know wheter to keep
+// internal statistics. Use NewCategoricalFromConfig for Markov chains and
+// dwell times.
+func NewCategorical(id string, seed int64, states []string, weights []float64, keepStats bool) (*Categorical, error) {
+	return NewCategoricalFromConfig(CategoricalConfig{ID: id, States: states, Weights: weights, Seed: seed, KeepStats: keepStats})
+}
+
+// NewCategoricalFromConfig creates a new Categorical from a
+// CategoricalConfig. The config is validated first and a *ConfigError
+// listing every invalid field is returned if it is not valid.
+func NewCategoricalFromConfig(cfg CategoricalConfig) (*Categorical, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	n := len(cfg.States)
+	c := &Categorical{
+		id:        cfg.ID,
+		rnd:       generateRandom(cfg.Seed, false),
+		states:    append([]string(nil), cfg.States...),
+		first:     true,
+		keepStats: cfg.KeepStats,
+		Stats:     NewCategoricalStats(cfg.ID),
+	}
+
+	if cfg.Transitions != nil {
+		c.transitions = make([][]float64, n)
+		for i, row := range cfg.Transitions {
+			c.transitions[i] = cumulative(row, n)
+		}
+
+		for i, s := range cfg.States {
+			if s == cfg.Initial {
+				c.state = i
+			}
+		}
+	} else {
+		c.weights = cumulative(cfg.Weights, n)
+	}
+
+	if cfg.MinDwell != nil {
+		c.minDwell = append([]int64(nil), cfg.MinDwell...)
+	}
+
+	c.Next()
+	return c, nil
+}
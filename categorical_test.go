@@ -0,0 +1,71 @@
+package fake
+
+import (
+	"fmt"
+	"strings"
+)
+
+func ExampleNewCategorical() {
+	fc, _ := NewCategorical("status", 1, []string{"200", "404", "500"}, []float64{90, 7, 3}, true)
+	fc.Values(1000)
+
+	fmt.Println(fc.Stats.CCounts)
+	// Output:
+	// map[200:903 404:74 500:24]
+}
+
+func ExampleNewCategoricalFromConfig() {
+	fc, _ := NewCategoricalFromConfig(CategoricalConfig{
+		ID:     "health",
+		States: []string{"UP", "DEGRADED", "DOWN"},
+		Transitions: [][]float64{
+			{95, 4, 1},   // from UP
+			{30, 60, 10}, // from DEGRADED
+			{50, 0, 50},  // from DOWN
+		},
+		MinDwell:  []int64{0, 3, 2},
+		Seed:      1,
+		KeepStats: true,
+	})
+
+	fmt.Println(strings.Join(fc.Values(40), " "))
+	fc.Values(10000)
+	fmt.Println(fc.Stats.CTransitions)
+	// Output:
+	// UP UP UP UP UP UP UP UP UP UP UP UP UP UP UP UP UP UP UP UP UP UP UP UP UP UP UP UP UP UP UP UP UP UP DEGRADED DEGRADED DEGRADED UP UP UP
+	// map[DEGRADED:map[DOWN:81 UP:218] DOWN:map[UP:157] UP:map[DEGRADED:299 DOWN:76]]
+}
+
+func ExampleCategoricalConfig_Validate() {
+	cfg := CategoricalConfig{
+		ID:          "job",
+		States:      []string{"PENDING", "RUNNING", "RUNNING"},
+		Transitions: [][]float64{{1, 1}, {0, -1, 0}},
+		MinDwell:    []int64{1, -1, 0},
+	}
+
+	for _, fe := range cfg.Validate().(*ConfigError).Fields {
+		fmt.Println(fe)
+	}
+	// Output:
+	// States[2] is a duplicate but was 'RUNNING'
+	// Transitions must have a row for each of the 3 states but was '2'
+	// Transitions[0] must have a weight for each of the 3 states but was '2'
+	// Transitions[1][1] cannot be less than 0 but was '-1'
+	// Transitions[1] must have at least one weight greater than 0 but was '[0 -1 0]'
+	// MinDwell[1] cannot be less than 0 but was '-1'
+}
+
+func ExampleCategorical_Snapshot() {
+	fc1, _ := NewCategorical("status", 1, []string{"200", "404", "500"}, []float64{90, 7, 3}, true)
+	fc1.Values(100)
+	snapshot, _ := fc1.Snapshot()
+
+	fc2, _ := NewCategorical("status", 1, []string{"200", "404", "500"}, []float64{90, 7, 3}, true)
+	fc2.Restore(snapshot)
+	fmt.Println(fc2.Values(20))
+	fmt.Println(fc1.Values(20))
+	// Output:
+	// [200 200 200 200 200 200 200 200 200 200 200 200 200 200 200 404 200 200 200 200]
+	// [200 200 200 200 200 200 200 200 200 200 200 200 200 200 200 404 200 200 200 200]
+}
@@ -0,0 +1,327 @@
+// Package chart renders fake Data series over a Time as SVG or PNG line
+// charts, e.g. for design reviews of a scenario.
+//
+// A Chart draws every series as a line with gaps for bad samples, shades
+// spike windows and the permanent bump, and overlays the minimum, maximum
+// and slope (see fake.DataStats) of every series:
+//
+//  c := chart.Chart{Title: "CPU", Time: fakeTime, Samples: 500}
+//  c.Series = []chart.Series{{Data: fakeData1, Gates: []fake.Gate{fakeRandom}}}
+//  err := c.SVG(f)
+//
+// Rendering is deterministic: the same generators always render the same
+// bytes, so charts can be compared against golden files.
+package chart
+
+import (
+	"image/color"
+	"math"
+	"strconv"
+	"time"
+
+	fake "github.com/powerpu/go-fake-ts"
+)
+
+// Series is a single Data drawn by a Chart.
+type Series struct {
+	// The series to draw.
+	Data *fake.Data
+
+	// Decide whether the data of the series is "good", bad samples leave a
+	// gap in the line. Optional.
+	Gates []fake.Gate
+
+	// The title of the series in the legend. Defaults to the ID of the Data.
+	Title string
+}
+
+// Chart draws Data series over a Time. The Time, the gates and all series
+// are advanced in lock-step the first time the chart is rendered, rendering
+// again (e.g. as PNG after SVG) draws the same samples.
+type Chart struct {
+	// The title above the chart. Optional.
+	Title string
+
+	// The time of every sample. Optional, the x axis shows sample positions
+	// without a Time.
+	Time *fake.Time
+
+	// Decide whether a sample is "good" for all series, bad samples leave a
+	// gap in every line. Optional.
+	Gates []fake.Gate
+
+	// The series to draw.
+	Series []Series
+
+	// How many samples to draw. Defaults to the samples of the first Data.
+	Samples int
+
+	// The size of the image in pixels. Defaults to 800 by 400.
+	Width  int
+	Height int
+
+	plot *plot
+}
+
+// The colors of the series, in order
+var palette = []color.RGBA{
+	{0x1f, 0x77, 0xb4, 0xff},
+	{0xff, 0x7f, 0x0e, 0xff},
+	{0x2c, 0xa0, 0x2c, 0xff},
+	{0xd6, 0x27, 0x28, 0xff},
+	{0x94, 0x67, 0xbd, 0xff},
+	{0x8c, 0x56, 0x4b, 0xff},
+}
+
+var (
+	black = color.RGBA{0x33, 0x33, 0x33, 0xff}
+	grey  = color.RGBA{0x99, 0x99, 0x99, 0xff}
+	light = color.RGBA{0xe5, 0xe5, 0xe5, 0xff}
+	white = color.RGBA{0xff, 0xff, 0xff, 0xff}
+)
+
+// Kinds of shapes
+const (
+	rectShape = iota
+	lineShape
+	textShape
+)
+
+// shape is a single shape of a plot in pixels. SVG and PNG draw the same
+// shapes.
+type shape struct {
+	kind    int
+	points  [][2]float64
+	color   color.RGBA
+	opacity float64
+	width   float64 // Of lines in SVG, 1 unless set
+	dashed  bool
+	text    string
+	anchor  string // "start", "middle" or "end"
+}
+
+// plot is everything a chart draws.
+type plot struct {
+	width, height int
+	shapes        []shape
+}
+
+// seriesData holds the collected samples of a series.
+type seriesData struct {
+	title   string
+	cfg     fake.DataConfig
+	start   int64
+	values  []float64
+	spiking []bool
+	stats   *fake.DataStats
+}
+
+// render collects the samples and lays out the plot once.
+func (c *Chart) render() *plot {
+	if c.plot != nil {
+		return c.plot
+	}
+
+	samples := c.Samples
+	if samples <= 0 && len(c.Series) > 0 {
+		samples = int(c.Series[0].Data.Config().Samples)
+	}
+
+	width, height := c.Width, c.Height
+	if width <= 0 {
+		width = 800
+	}
+	if height <= 0 {
+		height = 400
+	}
+
+	series := make([]seriesData, len(c.Series))
+	for j, s := range c.Series {
+		cfg := s.Data.Config()
+
+		title := s.Title
+		if title == "" {
+			title = cfg.ID
+		}
+
+		series[j] = seriesData{
+			title:   title,
+			cfg:     cfg,
+			start:   s.Data.Index(),
+			values:  make([]float64, samples),
+			spiking: make([]bool, samples),
+			stats:   fake.NewDataStats(cfg.ID, cfg.From, cfg.To),
+		}
+	}
+
+	times := make([]time.Time, samples)
+	for i := 0; i < samples; i++ {
+		good := fake.AllGood(c.Gates...)
+		if c.Time != nil {
+			times[i] = c.Time.Time()
+			c.Time.Next()
+		}
+
+		for j, s := range c.Series {
+			v := s.Data.Float()
+			series[j].spiking[i] = s.Data.Spiking()
+			if good && fake.AllGood(s.Gates...) {
+				series[j].stats.Add(v)
+			} else {
+				v = math.NaN()
+			}
+			series[j].values[i] = v
+
+			s.Data.Next()
+			for _, g := range s.Gates {
+				g.Next()
+			}
+		}
+
+		for _, g := range c.Gates {
+			g.Next()
+		}
+	}
+
+	c.plot = c.layout(width, height, samples, times, series)
+	return c.plot
+}
+
+// layout turns the collected samples into shapes.
+func (c *Chart) layout(width int, height int, samples int, times []time.Time, series []seriesData) *plot {
+	p := &plot{width: width, height: height}
+
+	left, right, top, bottom := 60.0, float64(width)-20, 30.0+14*float64(len(series)), float64(height)-30
+
+	// The plot always shows the limits
+	lo, hi := math.Inf(1), math.Inf(-1)
+	for _, s := range series {
+		lo, hi = math.Min(lo, math.Min(s.cfg.From, s.cfg.To)), math.Max(hi, math.Max(s.cfg.From, s.cfg.To))
+		for _, v := range s.values {
+			if !math.IsNaN(v) {
+				lo, hi = math.Min(lo, v), math.Max(hi, v)
+			}
+		}
+	}
+
+	if math.IsInf(lo, 0) || hi == lo {
+		lo, hi = lo-1, hi+1
+	}
+
+	pad := (hi - lo) * 0.05
+	lo, hi = lo-pad, hi+pad
+
+	x := func(i float64) float64 {
+		if samples < 2 {
+			return left
+		}
+
+		return left + i*(right-left)/float64(samples-1)
+	}
+
+	y := func(v float64) float64 {
+		return top + (hi-v)/(hi-lo)*(bottom-top)
+	}
+
+	step := (x(1) - x(0)) / 2
+
+	p.add(shape{kind: rectShape, points: [][2]float64{{0, 0}, {float64(width), float64(height)}}, color: white, opacity: 1})
+
+	// Grid and y axis
+	for t := 0; t <= 4; t++ {
+		v := lo + (hi-lo)*float64(t)/4
+		p.add(shape{kind: lineShape, points: [][2]float64{{left, y(v)}, {right, y(v)}}, color: light, opacity: 1})
+		p.add(shape{kind: textShape, points: [][2]float64{{left - 6, y(v) + 4}}, color: grey, opacity: 1, text: number(v), anchor: "end"})
+	}
+
+	// x axis
+	for t := 0; t <= 4 && samples > 0; t++ {
+		i := (samples - 1) * t / 4
+		label := strconv.Itoa(i)
+		if c.Time != nil {
+			label = times[i].UTC().Format("2006-01-02 15:04")
+		}
+
+		anchor := "middle"
+		switch t {
+		case 0:
+			anchor = "start"
+		case 4:
+			anchor = "end"
+		}
+
+		p.add(shape{kind: lineShape, points: [][2]float64{{x(float64(i)), bottom}, {x(float64(i)), bottom + 4}}, color: grey, opacity: 1})
+		p.add(shape{kind: textShape, points: [][2]float64{{x(float64(i)), bottom + 18}}, color: grey, opacity: 1, text: label, anchor: anchor})
+	}
+
+	for j, s := range series {
+		col := palette[j%len(palette)]
+
+		// Spike windows
+		for i := 0; i < samples; i++ {
+			if !s.spiking[i] {
+				continue
+			}
+
+			from := i
+			for i < samples && s.spiking[i] {
+				i++
+			}
+
+			p.add(shape{kind: rectShape, points: [][2]float64{{math.Max(left, x(float64(from))-step), top}, {math.Min(right, x(float64(i-1))+step), bottom}}, color: col, opacity: 0.12})
+		}
+
+		// The permanent bump from its onset on
+		if onset := s.cfg.PermaBumpAt - s.start; s.cfg.PermaBumpAt > 0 && s.cfg.PermaBumpSmoother > 0 && onset >= 0 && onset < int64(samples) {
+			p.add(shape{kind: rectShape, points: [][2]float64{{x(float64(onset)), top}, {right, bottom}}, color: grey, opacity: 0.1})
+			p.add(shape{kind: lineShape, points: [][2]float64{{x(float64(onset)), top}, {x(float64(onset)), bottom}}, color: col, opacity: 1, dashed: true})
+		}
+
+		// The line, broken at bad samples
+		var line [][2]float64
+		for i, v := range s.values {
+			if !math.IsNaN(v) {
+				line = append(line, [2]float64{x(float64(i)), y(v)})
+			}
+
+			if (math.IsNaN(v) || i == len(s.values)-1) && len(line) > 0 {
+				p.add(shape{kind: lineShape, points: line, color: col, opacity: 1, width: 1.5})
+				line = nil
+			}
+		}
+
+		// Overlay of the stats of the drawn samples
+		legend := s.title
+		if s.stats.CTotal > 0 {
+			s.stats.JSON() // Calculates the slopes
+			p.add(shape{kind: lineShape, points: [][2]float64{{left, y(s.stats.CMin)}, {right, y(s.stats.CMin)}}, color: col, opacity: 0.6, dashed: true})
+			p.add(shape{kind: lineShape, points: [][2]float64{{left, y(s.stats.CMax)}, {right, y(s.stats.CMax)}}, color: col, opacity: 0.6, dashed: true})
+
+			legend += "  min " + number(s.stats.CMin) + "  max " + number(s.stats.CMax) + "  slope " + number(s.stats.CSlope)
+		}
+
+		p.add(shape{kind: textShape, points: [][2]float64{{left, 30 + 14*float64(j+1) - 2}}, color: col, opacity: 1, text: legend, anchor: "start"})
+	}
+
+	if c.Title != "" {
+		p.add(shape{kind: textShape, points: [][2]float64{{left, 20}}, color: black, opacity: 1, text: c.Title, anchor: "start"})
+	}
+
+	// Frame of the plot
+	p.add(shape{kind: lineShape, points: [][2]float64{{left, top}, {left, bottom}, {right, bottom}}, color: grey, opacity: 1})
+
+	return p
+}
+
+func (p *plot) add(s shape) {
+	p.shapes = append(p.shapes, s)
+}
+
+// number formats a value for a label.
+func number(v float64) string {
+	if math.IsNaN(v) {
+		return "n/a"
+	}
+
+	return strconv.FormatFloat(v, 'g', 4, 64)
+}
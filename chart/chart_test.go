@@ -0,0 +1,80 @@
+package chart
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"image/png"
+	"os"
+	"time"
+
+	fake "github.com/powerpu/go-fake-ts"
+)
+
+func ExampleChart_SVG() {
+	ft, _ := fake.NewTime("ts", time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), 60000, 0, 0, false)
+	cpu, _ := fake.NewDataFromConfig(fake.NewDataConfig("cpu", 8, fake.WithRange(0, 100), fake.WithSlope(5), fake.WithSpike(4, 1, 90, 1)))
+	fp, _ := fake.NewPattern("collector", 3, 1, false)
+
+	c := Chart{Title: "CPU", Time: ft, Width: 300, Height: 120}
+	c.Series = []Series{{Data: cpu, Gates: []fake.Gate{fp}}}
+	c.SVG(os.Stdout)
+	// Output:
+	// <svg xmlns="http://www.w3.org/2000/svg" width="300" height="120" viewBox="0 0 300 120" font-family="sans-serif" font-size="11">
+	// <rect x="0" y="0" width="300" height="120" fill="#ffffff"/>
+	// <polyline points="60,90 280,90" fill="none" stroke="#e5e5e5" stroke-width="1"/>
+	// <text x="54" y="94" fill="#999999" text-anchor="end">-5</text>
+	// <polyline points="60,78.5 280,78.5" fill="none" stroke="#e5e5e5" stroke-width="1"/>
+	// <text x="54" y="82.5" fill="#999999" text-anchor="end">22.5</text>
+	// <polyline points="60,67 280,67" fill="none" stroke="#e5e5e5" stroke-width="1"/>
+	// <text x="54" y="71" fill="#999999" text-anchor="end">50</text>
+	// <polyline points="60,55.5 280,55.5" fill="none" stroke="#e5e5e5" stroke-width="1"/>
+	// <text x="54" y="59.5" fill="#999999" text-anchor="end">77.5</text>
+	// <polyline points="60,44 280,44" fill="none" stroke="#e5e5e5" stroke-width="1"/>
+	// <text x="54" y="48" fill="#999999" text-anchor="end">105</text>
+	// <polyline points="60,90 60,94" fill="none" stroke="#999999" stroke-width="1"/>
+	// <text x="60" y="108" fill="#999999" text-anchor="start">2020-01-01 00:00</text>
+	// <polyline points="91.43,90 91.43,94" fill="none" stroke="#999999" stroke-width="1"/>
+	// <text x="91.43" y="108" fill="#999999" text-anchor="middle">2020-01-01 00:01</text>
+	// <polyline points="154.29,90 154.29,94" fill="none" stroke="#999999" stroke-width="1"/>
+	// <text x="154.29" y="108" fill="#999999" text-anchor="middle">2020-01-01 00:03</text>
+	// <polyline points="217.14,90 217.14,94" fill="none" stroke="#999999" stroke-width="1"/>
+	// <text x="217.14" y="108" fill="#999999" text-anchor="middle">2020-01-01 00:05</text>
+	// <polyline points="280,90 280,94" fill="none" stroke="#999999" stroke-width="1"/>
+	// <text x="280" y="108" fill="#999999" text-anchor="end">2020-01-01 00:07</text>
+	// <rect x="138.57" y="44" width="141.43" height="46" fill="#1f77b4" fill-opacity="0.12"/>
+	// <polyline points="60,67 91.43,64.91 122.86,62.82" fill="none" stroke="#1f77b4" stroke-width="1.5"/>
+	// <polyline points="185.71,50.27 217.14,50.27 248.57,54.45" fill="none" stroke="#1f77b4" stroke-width="1.5"/>
+	// <polyline points="60,67 280,67" fill="none" stroke="#1f77b4" stroke-width="1" stroke-opacity="0.6" stroke-dasharray="4 4"/>
+	// <polyline points="60,50.27 280,50.27" fill="none" stroke="#1f77b4" stroke-width="1" stroke-opacity="0.6" stroke-dasharray="4 4"/>
+	// <text x="60" y="42" fill="#1f77b4" text-anchor="start">cpu  min 50  max 90  slope 8.143</text>
+	// <text x="60" y="20" fill="#333333" text-anchor="start">CPU</text>
+	// <polyline points="60,44 60,90 280,90" fill="none" stroke="#999999" stroke-width="1"/>
+	// </svg>
+}
+
+// Rendering the same generators again renders the same bytes.
+func ExampleChart_PNG() {
+	render := func() []byte {
+		cpu, _ := fake.NewDataFromConfig(fake.NewDataConfig("cpu", 500, fake.WithRange(0, 100), fake.WithBump(50), fake.WithRandom(1, 0.5), fake.WithSpike(100, 10, 90, 5), fake.WithPermaBump(400, 20, 20)))
+		mem, _ := fake.NewDataFromConfig(fake.NewDataConfig("mem", 500, fake.WithRange(0, 100), fake.WithBump(30), fake.WithRandom(2, 0.5)))
+		fr, _ := fake.NewRandom("collector", 3, 0.98, false)
+
+		c := Chart{Title: "host-1", Gates: []fake.Gate{fr}}
+		c.Series = []Series{{Data: cpu}, {Data: mem, Title: "memory"}}
+
+		var buf bytes.Buffer
+		c.PNG(&buf)
+		return buf.Bytes()
+	}
+
+	a, b := render(), render()
+	img, _ := png.Decode(bytes.NewReader(a))
+	fmt.Println(img.Bounds())
+	fmt.Println(bytes.Equal(a, b))
+	fmt.Printf("%x\n", sha256.Sum256(a))
+	// Output:
+	// (0,0)-(800,400)
+	// true
+	// 6fc9ce6cf2464ec4b39c142abc7ea10a3c00a2557a52c9fe759251a5fd9b2779
+}
@@ -0,0 +1,117 @@
+package chart
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+	"math"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+)
+
+// PNG renders the chart as a PNG image. It draws the same shapes as SVG
+// with plain 1 pixel lines and a fixed size font.
+func (c *Chart) PNG(w io.Writer) error {
+	p := c.render()
+
+	img := image.NewRGBA(image.Rect(0, 0, p.width, p.height))
+	for _, s := range p.shapes {
+		switch s.kind {
+		case rectShape:
+			r := image.Rect(round(s.points[0][0]), round(s.points[0][1]), round(s.points[1][0]), round(s.points[1][1]))
+			draw.Draw(img, r, image.NewUniform(blend(s.color, s.opacity)), image.Point{}, draw.Over)
+		case lineShape:
+			col := blend(s.color, s.opacity)
+			n := 0 // Pixels drawn so far, for the dashes
+			for i := 1; i < len(s.points); i++ {
+				n = line(img, s.points[i-1], s.points[i], col, s.dashed, n)
+			}
+			if len(s.points) == 1 {
+				line(img, s.points[0], s.points[0], col, false, 0)
+			}
+		case textShape:
+			d := font.Drawer{Dst: img, Src: image.NewUniform(blend(s.color, s.opacity)), Face: basicfont.Face7x13}
+			x := fixed.I(round(s.points[0][0]))
+			switch s.anchor {
+			case "middle":
+				x -= d.MeasureString(s.text) / 2
+			case "end":
+				x -= d.MeasureString(s.text)
+			}
+
+			d.Dot = fixed.Point26_6{X: x, Y: fixed.I(round(s.points[0][1]))}
+			d.DrawString(s.text)
+		}
+	}
+
+	return png.Encode(w, img)
+}
+
+// line draws a line from a to b with Bresenham's algorithm. Dashed lines
+// alternate 4 pixels on and 4 pixels off, continuing from the n pixels
+// already drawn. It returns the pixels drawn including this line.
+func line(img *image.RGBA, a [2]float64, b [2]float64, col color.Color, dashed bool, n int) int {
+	x0, y0, x1, y1 := round(a[0]), round(a[1]), round(b[0]), round(b[1])
+
+	dx, dy := abs(x1-x0), -abs(y1-y0)
+	sx, sy := 1, 1
+	if x0 > x1 {
+		sx = -1
+	}
+	if y0 > y1 {
+		sy = -1
+	}
+
+	src := image.NewUniform(col)
+	for e := dx + dy; ; n++ {
+		if !dashed || n%8 < 4 {
+			draw.Draw(img, image.Rect(x0, y0, x0+1, y0+1), src, image.Point{}, draw.Over)
+		}
+
+		if x0 == x1 && y0 == y1 {
+			return n
+		}
+
+		e2 := 2 * e
+		if e2 >= dy {
+			e += dy
+			x0 += sx
+		}
+		if e2 <= dx {
+			e += dx
+			y0 += sy
+		}
+	}
+}
+
+// blend returns a color with the opacity applied, premultiplied as image/draw
+// expects.
+func blend(c color.RGBA, opacity float64) color.RGBA {
+	if opacity >= 1 {
+		return c
+	}
+
+	a := uint8(math.Round(opacity * 255))
+	return color.RGBA{
+		R: uint8(uint16(c.R) * uint16(a) / 255),
+		G: uint8(uint16(c.G) * uint16(a) / 255),
+		B: uint8(uint16(c.B) * uint16(a) / 255),
+		A: a,
+	}
+}
+
+func round(v float64) int {
+	return int(math.Round(v))
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+
+	return v
+}
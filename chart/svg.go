@@ -0,0 +1,68 @@
+package chart
+
+import (
+	"bufio"
+	"fmt"
+	"image/color"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// SVG renders the chart as an SVG image.
+func (c *Chart) SVG(w io.Writer) error {
+	p := c.render()
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="sans-serif" font-size="11">`+"\n", p.width, p.height, p.width, p.height)
+
+	for _, s := range p.shapes {
+		switch s.kind {
+		case rectShape:
+			a, b := s.points[0], s.points[1]
+			fmt.Fprintf(bw, `<rect x="%v" y="%v" width="%v" height="%v" fill="%v"%v/>`+"\n", coord(a[0]), coord(a[1]), coord(b[0]-a[0]), coord(b[1]-a[1]), hex(s.color), opacity("fill-opacity", s.opacity))
+		case lineShape:
+			points := make([]string, len(s.points))
+			for i, pt := range s.points {
+				points[i] = coord(pt[0]) + "," + coord(pt[1])
+			}
+
+			width := s.width
+			if width == 0 {
+				width = 1
+			}
+
+			dash := ""
+			if s.dashed {
+				dash = ` stroke-dasharray="4 4"`
+			}
+
+			fmt.Fprintf(bw, `<polyline points="%v" fill="none" stroke="%v" stroke-width="%v"%v%v/>`+"\n", strings.Join(points, " "), hex(s.color), width, opacity("stroke-opacity", s.opacity), dash)
+		case textShape:
+			fmt.Fprintf(bw, `<text x="%v" y="%v" fill="%v" text-anchor="%v">%v</text>`+"\n", coord(s.points[0][0]), coord(s.points[0][1]), hex(s.color), s.anchor, escaper.Replace(s.text))
+		}
+	}
+
+	bw.WriteString("</svg>\n")
+	return bw.Flush()
+}
+
+var escaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", `"`, "&quot;")
+
+// coord formats a coordinate with at most 2 decimals.
+func coord(v float64) string {
+	return strconv.FormatFloat(math.Round(v*100)/100, 'f', -1, 64)
+}
+
+func hex(c color.RGBA) string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
+
+func opacity(attr string, o float64) string {
+	if o >= 1 {
+		return ""
+	}
+
+	return " " + attr + `="` + strconv.FormatFloat(o, 'f', -1, 64) + `"`
+}
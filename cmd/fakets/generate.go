@@ -0,0 +1,129 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	fake "github.com/powerpu/go-fake-ts"
+	"github.com/powerpu/go-fake-ts/csvout"
+	"github.com/powerpu/go-fake-ts/influxout"
+	"github.com/powerpu/go-fake-ts/jsonlout"
+)
+
+// generate writes every sample of a scenario in the chosen format.
+func generate(args []string, stdout io.Writer, stderr io.Writer) int {
+	fs := flag.NewFlagSet("generate", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+
+	var sf specFlags
+	sf.register(fs)
+
+	format := fs.String("format", "csv", "output `format`: csv, jsonl or influx")
+	output := fs.String("o", "", "write to this `file` instead of stdout")
+	header := fs.Bool("header", true, "csv: write a header row")
+	precision := fs.Int("precision", -1, "csv: `digits` after the decimal point (-1 for as many as needed)")
+	timeFormat := fs.String("timeFormat", "rfc3339", "csv: timestamp `format`: rfc3339, s, ms or ns")
+	tmpl := fs.String("template", "", "jsonl: text/template rendering every row (see package jsonlout)")
+	measurement := fs.String("measurement", "fake", "influx: `name` of the measurement")
+	influxPrecision := fs.String("influxPrecision", "ns", "influx: timestamp `precision`: ns, us, ms or s")
+
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	// Check the format flags before the output file is created so a typo
+	// does not truncate it
+	if *format != "csv" && *format != "jsonl" && *format != "influx" {
+		fmt.Fprintf(stderr, "fakets: -format must be csv, jsonl or influx but was '%v'\n", *format)
+		return 2
+	}
+
+	tf, ok := map[string]csvout.TimeFormat{"rfc3339": csvout.RFC3339, "s": csvout.EpochSeconds, "ms": csvout.EpochMillis, "ns": csvout.EpochNanos}[*timeFormat]
+	if !ok {
+		fmt.Fprintf(stderr, "fakets: -timeFormat must be rfc3339, s, ms or ns but was '%v'\n", *timeFormat)
+		return 2
+	}
+
+	p, ok := map[string]influxout.Precision{"ns": influxout.Nanosecond, "us": influxout.Microsecond, "ms": influxout.Millisecond, "s": influxout.Second}[*influxPrecision]
+	if !ok {
+		fmt.Fprintf(stderr, "fakets: -influxPrecision must be ns, us, ms or s but was '%v'\n", *influxPrecision)
+		return 2
+	}
+
+	spec, count, err := sf.spec()
+	if err != nil {
+		fmt.Fprintln(stderr, "fakets:", err)
+		return 1
+	}
+
+	sc, err := fake.NewScenario(spec)
+	if err != nil {
+		fmt.Fprintln(stderr, "fakets:", err)
+		return 1
+	}
+
+	out := stdout
+	if *output != "" {
+		f, err := os.Create(*output)
+		if err != nil {
+			fmt.Fprintln(stderr, "fakets:", err)
+			return 1
+		}
+		defer f.Close()
+
+		out = f
+	}
+
+	switch *format {
+	case "csv":
+		w := csvout.NewWriter(out, sc.Time)
+		for _, s := range sc.Series {
+			w.Columns = append(w.Columns, csvout.Column{Name: s.ID, Value: s.Data, Gates: s.Gates})
+		}
+		w.Gates = sc.Gates
+		w.Header = *header
+		w.Precision = *precision
+		w.TimeFormat = tf
+
+		err = w.Write(int(count))
+	case "jsonl":
+		var columns []jsonlout.Column
+		mapping := []jsonlout.Mapping{{Key: "time", From: "time"}, {Key: "good", From: "good"}}
+		for _, s := range sc.Series {
+			columns = append(columns, jsonlout.Column{Name: s.ID, Value: s.Data, Gates: s.Gates})
+			mapping = append(mapping, jsonlout.Mapping{Key: s.ID, From: s.ID})
+		}
+
+		var w *jsonlout.Writer
+		if *tmpl != "" {
+			w, err = jsonlout.NewTemplateWriter(out, *tmpl, sc.Time, columns...)
+		} else {
+			w, err = jsonlout.NewMappingWriter(out, mapping, sc.Time, columns...)
+		}
+
+		if err == nil {
+			w.Gates = sc.Gates
+			err = w.Write(int(count))
+		}
+	case "influx":
+		series := influxout.Series{Measurement: *measurement}
+		for _, s := range sc.Series {
+			series.Fields = append(series.Fields, influxout.Field{Key: s.ID, Value: s.Data, Gates: s.Gates})
+		}
+
+		e := influxout.NewEncoder(out, sc.Time, series)
+		e.Gates = sc.Gates
+		e.Precision = p
+
+		err = e.Encode(int(count))
+	}
+
+	if err != nil {
+		fmt.Fprintln(stderr, "fakets:", err)
+		return 1
+	}
+
+	return 0
+}
@@ -0,0 +1,67 @@
+// Command fakets generates fake time series from the command line.
+//
+// Usage:
+//
+//  fakets generate [flags]     write samples as CSV, JSON Lines or InfluxDB line protocol
+//  fakets preview [flags]      print the first rows and the stats of every generator
+//  fakets validate [flags]     check a scenario without generating anything
+//  fakets playground [flags]   tune a series in the browser
+//
+// A scenario is either read from a JSON or YAML file (see fake.ScenarioSpec)
+//
+//  fakets generate -spec scenario.yaml -format csv -o out.csv
+//
+// or described by flags, which map onto the parameters of NewTime, NewData,
+// NewPattern and NewRandom and describe a single series:
+//
+//  fakets generate -samples 1440 -increment 60000 -from 0 -to 100 \
+//      -useRandom -seed 1 -pattern 23:1 -format influx -measurement cpu
+//
+// Run "fakets <command> -h" to list every flag.
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+const usage = `Usage: fakets <command> [flags]
+
+Commands:
+  generate     write samples as CSV, JSON Lines or InfluxDB line protocol
+  preview      print the first rows and the stats of every generator
+  validate     check a scenario without generating anything
+  playground   tune a series in the browser
+
+Run "fakets <command> -h" to list every flag.
+`
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run runs a command and returns the exit code.
+func run(args []string, stdout io.Writer, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprint(stderr, usage)
+		return 2
+	}
+
+	switch args[0] {
+	case "generate":
+		return generate(args[1:], stdout, stderr)
+	case "preview":
+		return preview(args[1:], stdout, stderr)
+	case "validate":
+		return validate(args[1:], stdout, stderr)
+	case "playground":
+		return playgroundCmd(args[1:], stdout, stderr)
+	case "-h", "-help", "--help", "help":
+		fmt.Fprint(stdout, usage)
+		return 0
+	}
+
+	fmt.Fprintf(stderr, "fakets: unknown command '%v'\n\n%v", args[0], usage)
+	return 2
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+func Example_generate() {
+	run([]string{"generate", "-samples", "5", "-increment", "3600000", "-slope", "1", "-pattern", "2:1", "-timeFormat", "s"}, os.Stdout, os.Stderr)
+	// Output:
+	// Timestamp,value
+	// 1577836800,50
+	// 1577840400,51
+	// 1577847600,53
+	// 1577851200,54
+}
+
+// A master seed derives the seeds that are not set explicitly.
+func Example_generateMasterSeed() {
+	run([]string{"generate", "-samples", "3", "-useRandom", "-pctGood", "0.5", "-dataPctGood", "0.5", "-masterSeed", "5", "-precision", "2"}, os.Stdout, os.Stderr)
+	run([]string{"generate", "-samples", "3", "-masterSeed", "5", "-timeSeed", "3"}, os.Stdout, os.Stdout)
+	// Output:
+	// Timestamp,value
+	// 2020-01-01T00:00:00Z,
+	// 2020-01-01T00:01:00Z,
+	// 2020-01-01T00:02:00Z,-21.87
+	// fakets: invalid fake scenario with id 'time': Time.Seed cannot be set together with MasterSeed but was '3'
+}
+
+// An invalid flag leaves an existing output file alone.
+func Example_generateInvalidFormat() {
+	dir, _ := os.MkdirTemp("", "fakets")
+	defer os.RemoveAll(dir)
+
+	out := filepath.Join(dir, "out.csv")
+	os.WriteFile(out, []byte("kept\n"), 0o644)
+
+	fmt.Println(run([]string{"generate", "-samples", "2", "-format", "xml", "-o", out}, os.Stdout, os.Stdout))
+	fmt.Println(run([]string{"generate", "-samples", "2", "-timeFormat", "h", "-o", out}, os.Stdout, os.Stdout))
+	b, _ := os.ReadFile(out)
+	fmt.Print(string(b))
+	// Output:
+	// fakets: -format must be csv, jsonl or influx but was 'xml'
+	// 2
+	// fakets: -timeFormat must be rfc3339, s, ms or ns but was 'h'
+	// 2
+	// kept
+}
+
+func Example_generateSpec() {
+	run([]string{"generate", "-spec", "../../testdata/scenario.yaml", "-format", "influx", "-measurement", "host", "-influxPrecision", "s", "-until", "2020-02-07T03:00:00Z"}, os.Stdout, os.Stderr)
+	// Output:
+	// host cpu=-57.280531906981736,memory=50 1581033600
+	// host cpu=-63.071999277046245,memory=51 1581037200
+	// host cpu=-63.88021576640003,memory=52 1581040800
+}
+
+func Example_preview() {
+	run([]string{"preview", "-samples", "100", "-rows", "3", "-from", "0", "-to", "10", "-slope", "0.1", "-pctGood", "0.5"}, os.Stdout, os.Stderr)
+	// Output:
+	// INDEX  TIME                  GOOD   value
+	// 0      2020-01-01T00:00:00Z  false  5.00
+	// 1      2020-01-01T00:01:00Z  false  5.10
+	// 2      2020-01-01T00:02:00Z  false  5.20
+	//
+	// Stats after 100 samples:
+	// {
+	//   "random": {
+	//     "id": "random",
+	//     "cumulativeTotal": 100,
+	//     "cumulativeGoodCount": 51,
+	//     "cumulativeBadCount": 49,
+	//     "cumulativeRatio": 0.51,
+	//     "slotTotal": 100,
+	//     "slotGoodCount": 51,
+	//     "slotBadCount": 49,
+	//     "slotGoodRatio": 0.51,
+	//     "cumulativeBursts": 26,
+	//     "cumulativeMeanBurst": 1.8846153846153846,
+	//     "cumulativeMaxBurst": 6,
+	//     "slotBursts": 26,
+	//     "slotMeanBurst": 1.8846153846153846,
+	//     "slotMaxBurst": 6,
+	//     "currentBurst": 0
+	//   },
+	//   "time": {
+	//     "id": "time",
+	//     "cumulativeTotal": 100,
+	//     "cumulativeEarliestTime": "2020-01-01T00:00:00Z",
+	//     "cumulativeLatestTime": "2020-01-01T01:39:00Z",
+	//     "slotTotal": 100,
+	//     "slotEarliestTime": "2020-01-01T00:00:00Z",
+	//     "slotLatestTime": "2020-01-01T01:39:00Z"
+	//   },
+	//   "value": {
+	//     "id": "value",
+	//     "from": 0,
+	//     "to": 10,
+	//     "seed": 0,
+	//     "cumulativeTotal": 100,
+	//     "cumulativeMinimum": 5,
+	//     "cumulativeHitMinimumAt": 0,
+	//     "cumulativePointsBelowLowerLimit": 0,
+	//     "cumulativePointsAtLowerLimit": 0,
+	//     "cumulativeMaximum": 14.9,
+	//     "cumulativeHitMaximumAt": 51,
+	//     "cumulativePointsAboveUpperLimit": 49,
+	//     "cumulativePointsAtUpperLimit": 1,
+	//     "slotTotal": 100,
+	//     "slotMinimum": 5,
+	//     "slotHitMinimumAt": 0,
+	//     "slotPointsBelowLowerLimit": 0,
+	//     "slotPointsAtLowerLimit": 0,
+	//     "slotMaximum": 14.9,
+	//     "slotHitMaximumAt": 51,
+	//     "slotPointsAboveUpperLimit": 49,
+	//     "slotPointsAtUpperLimit": 1,
+	//     "cumulativeSlope": 0.10000000000000002,
+	//     "slotSlope": 0.10000000000000002
+	//   }
+	// }
+}
+
+func Example_validate() {
+	run([]string{"validate", "-from", "10", "-to", "5", "-useRandom", "-bias", "2"}, os.Stdout, os.Stderr)
+	// Output:
+	// invalid fake scenario with id 'time':
+	//   Data[0].From cannot be greater than To (5) but was '10'
+	//   Data[0].Bias must be between 0 and 1 but was '2'
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+
+	"github.com/powerpu/go-fake-ts/playground"
+)
+
+// playgroundCmd serves a web page to tune the parameters of the first series
+// of a scenario until the server fails.
+func playgroundCmd(args []string, stdout io.Writer, stderr io.Writer) int {
+	fs := flag.NewFlagSet("playground", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+
+	var sf specFlags
+	sf.register(fs)
+
+	addr := fs.String("addr", "localhost:8080", "`address` to listen on")
+
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	spec, count, err := sf.spec()
+	if err == nil && len(spec.Data) == 0 {
+		err = errors.New("the scenario has no series to tune")
+	}
+	if err != nil {
+		fmt.Fprintln(stderr, "fakets:", err)
+		return 1
+	}
+
+	cfg := spec.Data[0].DataConfig
+	if cfg.Samples <= 0 {
+		cfg.Samples = count
+	}
+
+	l, err := net.Listen("tcp", *addr)
+	if err != nil {
+		fmt.Fprintln(stderr, "fakets:", err)
+		return 1
+	}
+
+	fmt.Fprintf(stdout, "playground for '%v' on http://%v/\n", cfg.ID, l.Addr())
+	if err := http.Serve(l, playground.NewServer(cfg)); err != nil {
+		fmt.Fprintln(stderr, "fakets:", err)
+		return 1
+	}
+
+	return 0
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	fake "github.com/powerpu/go-fake-ts"
+	"github.com/powerpu/go-fake-ts/termchart"
+)
+
+// preview prints the first rows of a scenario and the stats of every
+// generator after all samples.
+func preview(args []string, stdout io.Writer, stderr io.Writer) int {
+	fs := flag.NewFlagSet("preview", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+
+	var sf specFlags
+	sf.register(fs)
+
+	rows := fs.Int("rows", 10, "`number` of rows to print")
+	chart := fs.Bool("chart", false, "draw every series in the terminal")
+	width := fs.Int("chartWidth", 60, "`width` of the charts in characters")
+
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	spec, count, err := sf.spec()
+	if err != nil {
+		fmt.Fprintln(stderr, "fakets:", err)
+		return 1
+	}
+
+	// Stats are the point of a preview
+	spec.Time.KeepStats = true
+	keepStats(spec.Gates)
+	for i := range spec.Data {
+		spec.Data[i].KeepStats = true
+		keepStats(spec.Data[i].Gates)
+	}
+
+	sc, err := fake.NewScenario(spec)
+	if err != nil {
+		fmt.Fprintln(stderr, "fakets:", err)
+		return 1
+	}
+
+	tw := tabwriter.NewWriter(stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprint(tw, "INDEX\tTIME\tGOOD")
+	for _, s := range sc.Series {
+		fmt.Fprint(tw, "\t", s.ID)
+	}
+	fmt.Fprintln(tw)
+
+	// Only advance between rows so the stats cover exactly count samples and
+	// not the one after the last row
+	for i := int64(0); i < count; i++ {
+		if i > 0 {
+			sc.Next()
+		}
+
+		row := sc.Row()
+		if i >= int64(*rows) {
+			continue
+		}
+
+		fmt.Fprintf(tw, "%v\t%v\t%v", row.Index, row.Time.Format(time.RFC3339), row.Good)
+		for _, f := range row.Fields {
+			v := strconv.FormatFloat(f.Value, 'f', 2, 64)
+			if !f.Good {
+				v = "bad"
+			}
+			fmt.Fprint(tw, "\t", v)
+		}
+		fmt.Fprintln(tw)
+	}
+	tw.Flush()
+
+	var stats bytes.Buffer
+	json.Indent(&stats, []byte(sc.JSONStats()), "", "  ")
+	fmt.Fprintf(stdout, "\nStats after %v samples:\n%v\n", count, stats.String())
+
+	if *chart {
+		c := termchart.Chart{Samples: int(count), Width: *width}
+
+		// Every series gets its own scenario so shared sample gates advance
+		// once per sample
+		for i := range spec.Data {
+			sc, err := fake.NewScenario(spec)
+			if err != nil {
+				fmt.Fprintln(stderr, "fakets:", err)
+				return 1
+			}
+
+			s := sc.Series[i]
+			c.Series = append(c.Series, termchart.Series{Data: s.Data, Gates: append(sc.Gates, s.Gates...)})
+		}
+
+		fmt.Fprintln(stdout)
+		c.Render(stdout)
+	}
+
+	return 0
+}
+
+// keepStats turns on the stats of every gate, including the gates of
+// combinators.
+func keepStats(gates []fake.GateSpec) {
+	for i := range gates {
+		gates[i].KeepStats = true
+		keepStats(gates[i].Gates)
+	}
+}
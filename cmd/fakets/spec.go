@@ -0,0 +1,210 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	fake "github.com/powerpu/go-fake-ts"
+)
+
+// specFlags describes a scenario with a file or with flags.
+type specFlags struct {
+	fs    *flag.FlagSet
+	path  string
+	until string
+	data  fake.DataConfig
+	time  fake.TimeConfig
+	start string
+
+	pattern    string
+	pctGood    float64
+	meanBurst  float64
+	gateSeed   int64
+	dataGood   float64
+	dataSeed   int64
+	masterSeed int64
+}
+
+// register adds the flags describing a scenario to fs.
+func (sf *specFlags) register(fs *flag.FlagSet) {
+	sf.fs = fs
+	sf.data = fake.NewDataConfig("value", 1000)
+
+	fs.StringVar(&sf.path, "spec", "", "read the scenario from a JSON or YAML `file` instead of flags")
+	fs.StringVar(&sf.until, "until", "", "generate samples up to this RFC 3339 `time` instead of -samples")
+	fs.Int64Var(&sf.masterSeed, "masterSeed", -1, "derive every seed from this master `seed` (-1 to set seeds individually)")
+
+	// NewTime
+	fs.StringVar(&sf.start, "start", "2020-01-01T00:00:00Z", "RFC 3339 `time` of the first sample")
+	fs.IntVar(&sf.time.Increment, "increment", 60000, "`milliseconds` between samples")
+	fs.IntVar(&sf.time.Variance, "variance", 0, "random variance of every timestamp in `milliseconds`")
+	fs.IntVar(&sf.time.Direction, "direction", 0, "direction of the variance (< 0 always negative, 0 either, > 0 always positive)")
+	fs.Int64Var(&sf.time.Seed, "timeSeed", 1, "`seed` of the time variance")
+
+	// NewData
+	d := &sf.data
+	fs.StringVar(&d.ID, "id", d.ID, "`id` (and column name) of the series")
+	fs.Int64Var(&d.Samples, "samples", d.Samples, "number of samples")
+	fs.Float64Var(&d.StretchStart, "stretchStart", d.StretchStart, "stretch (> 1) or squish (< 1) at the start")
+	fs.Float64Var(&d.StretchEnd, "stretchEnd", d.StretchEnd, "stretch (> 1) or squish (< 1) at the end")
+	fs.Float64Var(&d.Slope, "slope", d.Slope, "slope of the series")
+	fs.Float64Var(&d.Bump, "bump", d.Bump, "shift every value up or down")
+	fs.Float64Var(&d.From, "from", d.From, "lowest value of the range")
+	fs.Float64Var(&d.To, "to", d.To, "highest value of the range")
+	fs.BoolVar(&d.LimitLower, "limitLower", d.LimitLower, "never go below -from")
+	fs.BoolVar(&d.LimitUpper, "limitUpper", d.LimitUpper, "never go above -to")
+	fs.Int64Var(&d.PermaBumpAt, "permaBumpAt", d.PermaBumpAt, "`sample` at which a permanent bump starts")
+	fs.Float64Var(&d.PermaBumpBy, "permaBumpBy", d.PermaBumpBy, "size of the permanent bump")
+	fs.Int64Var(&d.PermaBumpSmoother, "permaBumpSmoother", d.PermaBumpSmoother, "`samples` to smooth the permanent bump over")
+	fs.BoolVar(&d.UseRandom, "useRandom", d.UseRandom, "add a random walk")
+	fs.Int64Var(&d.Seed, "seed", d.Seed, "`seed` of the random walk (negative to seed from the current time)")
+	fs.Float64Var(&d.Bias, "bias", d.Bias, "bias of the random walk (0 to 1, 0.5 is neutral)")
+	fs.BoolVar(&d.CounterRandom, "counterRandom", d.CounterRandom, "use a counter-based random number generator")
+	fs.BoolVar(&d.Spike, "spike", d.Spike, "add spikes")
+	fs.Int64Var(&d.SpikeEvery, "spikeEvery", d.SpikeEvery, "`samples` between spikes")
+	fs.Int64Var(&d.SpikeSustain, "spikeSustain", d.SpikeSustain, "`samples` a spike lasts")
+	fs.Int64Var(&d.SpikeTo, "spikeTo", d.SpikeTo, "`percent` of -to a spike goes up to")
+	fs.BoolVar(&d.SpikeWobble, "spikeWobble", d.SpikeWobble, "wobble at the top of a spike")
+	fs.Int64Var(&d.SpikeWobbleFactor, "spikeWobbleFactor", d.SpikeWobbleFactor, "how much a spike wobbles")
+	fs.Int64Var(&d.SpikeSmoother, "spikeSmoother", d.SpikeSmoother, "`samples` to smooth a spike over")
+	fs.BoolVar(&d.Seasonality, "seasonality", d.Seasonality, "add seasonality")
+	fs.Func("waves", "comma separated `lengths` of up to 5 seasonality waves", func(s string) error {
+		var waves []int64
+		for _, w := range strings.Split(s, ",") {
+			v, err := strconv.ParseInt(strings.TrimSpace(w), 10, 64)
+			if err != nil {
+				return err
+			}
+			waves = append(waves, v)
+		}
+
+		fake.WithSeasonality(waves...)(d)
+		return nil
+	})
+
+	// NewPattern and NewRandom
+	fs.StringVar(&sf.pattern, "pattern", "", "sample gate of `good:bad` samples or a sequence, e.g. 23:1 or 'G*23 B'")
+	fs.Float64Var(&sf.pctGood, "pctGood", 0, "sample gate that is good this `fraction` of the time, e.g. 0.95")
+	fs.Float64Var(&sf.meanBurst, "meanBurst", 0, "make the -pctGood sample gate bad in bursts of this mean `length`, e.g. 10")
+	fs.Int64Var(&sf.gateSeed, "gateSeed", 1, "`seed` of the -pctGood sample gate")
+	fs.Float64Var(&sf.dataGood, "dataPctGood", 0, "data gate that is good this `fraction` of the time, e.g. 0.99")
+	fs.Int64Var(&sf.dataSeed, "dataSeed", 2, "`seed` of the -dataPctGood data gate")
+}
+
+// spec returns the scenario described by the file or the flags and the
+// number of samples to generate.
+func (sf *specFlags) spec() (fake.ScenarioSpec, int64, error) {
+	var spec fake.ScenarioSpec
+
+	if sf.path != "" {
+		f, err := os.Open(sf.path)
+		if err != nil {
+			return spec, 0, err
+		}
+		defer f.Close()
+
+		if spec, err = fake.ParseScenarioSpec(f, strings.TrimPrefix(filepath.Ext(sf.path), ".")); err != nil {
+			return spec, 0, err
+		}
+	} else {
+		// The seeds default to fixed values for repeatable output but a
+		// master seed derives every seed that was not set explicitly
+		if sf.masterSeed >= 0 {
+			set := map[string]bool{}
+			sf.fs.Visit(func(f *flag.Flag) { set[f.Name] = true })
+
+			for name, seed := range map[string]*int64{"timeSeed": &sf.time.Seed, "gateSeed": &sf.gateSeed, "dataSeed": &sf.dataSeed} {
+				if !set[name] {
+					*seed = 0
+				}
+			}
+		}
+
+		start, err := time.Parse(time.RFC3339, sf.start)
+		if err != nil {
+			return spec, 0, errors.New("-start must be an RFC 3339 time but was '" + sf.start + "'")
+		}
+
+		spec.Samples = sf.data.Samples
+		spec.Time = sf.time
+		spec.Time.ID = "time"
+		spec.Time.Start = start
+
+		if sf.pattern != "" && strings.ContainsAny(sf.pattern, "GB") {
+			spec.Gates = append(spec.Gates, fake.GateSpec{ID: "pattern", Type: "pattern", Sequence: sf.pattern})
+		} else if sf.pattern != "" {
+			good, bad, ok := strings.Cut(sf.pattern, ":")
+			g, err1 := strconv.Atoi(good)
+			b, err2 := strconv.Atoi(bad)
+			if !ok || err1 != nil || err2 != nil {
+				return spec, 0, errors.New("-pattern must be 'good:bad' or a sequence but was '" + sf.pattern + "'")
+			}
+
+			spec.Gates = append(spec.Gates, fake.GateSpec{ID: "pattern", Type: "pattern", Good: g, Bad: b})
+		}
+
+		if sf.pctGood > 0 && sf.meanBurst > 0 {
+			spec.Gates = append(spec.Gates, fake.GateSpec{ID: "burst", Type: "burst", Seed: sf.gateSeed, PctGood: sf.pctGood, MeanBurst: sf.meanBurst})
+		} else if sf.pctGood > 0 {
+			spec.Gates = append(spec.Gates, fake.GateSpec{ID: "random", Type: "random", Seed: sf.gateSeed, PctGood: sf.pctGood})
+		}
+
+		ds := fake.DataSpec{DataConfig: sf.data}
+		ds.Samples = 0
+		if sf.dataGood > 0 {
+			ds.Gates = append(ds.Gates, fake.GateSpec{ID: sf.data.ID + "-random", Type: "random", Seed: sf.dataSeed, PctGood: sf.dataGood})
+		}
+		spec.Data = append(spec.Data, ds)
+	}
+
+	if sf.masterSeed >= 0 {
+		spec.MasterSeed = &sf.masterSeed
+	}
+
+	if sf.until == "" {
+		return spec, samples(spec), nil
+	}
+
+	until, err := time.Parse(time.RFC3339, sf.until)
+	if err != nil {
+		return spec, 0, errors.New("-until must be an RFC 3339 time but was '" + sf.until + "'")
+	}
+
+	if spec.Time.Increment <= 0 {
+		return spec, 0, errors.New("-until needs an increment greater than 0")
+	}
+
+	count := int64(until.Sub(spec.Time.Start)/(time.Duration(spec.Time.Increment)*time.Millisecond)) + 1
+	if count < 1 {
+		return spec, 0, errors.New("-until must not be before the start")
+	}
+
+	// A series described by flags always spans every sample
+	if sf.path == "" {
+		spec.Samples = count
+	}
+
+	return spec, count, nil
+}
+
+// samples returns the number of samples to generate: the samples of the
+// scenario or, if it does not set any, of its longest series.
+func samples(spec fake.ScenarioSpec) int64 {
+	if spec.Samples > 0 {
+		return spec.Samples
+	}
+
+	var n int64
+	for _, ds := range spec.Data {
+		if ds.Samples > n {
+			n = ds.Samples
+		}
+	}
+
+	return n
+}
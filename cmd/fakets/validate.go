@@ -0,0 +1,42 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+
+	fake "github.com/powerpu/go-fake-ts"
+)
+
+// validate checks a scenario and lists every invalid field.
+func validate(args []string, stdout io.Writer, stderr io.Writer) int {
+	fs := flag.NewFlagSet("validate", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+
+	var sf specFlags
+	sf.register(fs)
+
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	spec, count, err := sf.spec()
+	if err == nil {
+		err = spec.Validate()
+	}
+
+	if ce, ok := err.(*fake.ConfigError); ok {
+		fmt.Fprintf(stdout, "invalid fake %v with id '%v':\n", ce.Kind, ce.ID)
+		for _, fe := range ce.Fields {
+			fmt.Fprintln(stdout, "  "+fe.Error())
+		}
+
+		return 1
+	} else if err != nil {
+		fmt.Fprintln(stdout, err)
+		return 1
+	}
+
+	fmt.Fprintf(stdout, "valid: %v samples of %v series\n", count, len(spec.Data))
+	return 0
+}
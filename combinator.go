@@ -0,0 +1,326 @@
+package fake
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Combinator is a Gate that combines other gates, e.g. a sample is only good
+// when both the network and the collector are up. Combinators are created
+// with And, Or, Xor, Not, AtLeast and Majority and can be combined again to
+// describe complex outages:
+//
+//  outage := fake.Or(fake.And(network, collector), maintenance).Named("outage")
+//
+// The combinator advances its gates itself so none of them should be advanced
+// by anything else, nor be part of more than one combinator. That includes
+// the same combinator: And(p, p) advances p twice every sample. Scenarios
+// cannot do this as every gate has a unique ID.
+//
+// Like the other types a combinator only keeps statistics when asked to, see
+// WithStats.
+type Combinator struct {
+	id        string
+	op        string
+	k         int
+	gates     []Gate
+	culprits  []string
+	keepStats bool
+	Stats     *CombinatorStats
+	v         bool
+}
+
+// CombinatorStats keeps track of various statistics of a Combinator while
+// it's running. Next to the statistics of a Pattern it counts which gates
+// caused the "bad" values.
+type CombinatorStats struct {
+	PatternStats
+
+	// Cumulative count of "bad" values caused by each gate, by gate ID.
+	CBadBy map[string]int64 `json:"cumulativeBadBy"`
+
+	// Slot count of "bad" values caused by each gate, by gate ID. This gets
+	// reset after every JSON() call.
+	BadBy map[string]int64 `json:"slotBadBy"`
+}
+
+// NewCombinatorStats creates the statistics of a Combinator.
+func NewCombinatorStats(id string) *CombinatorStats {
+	return &CombinatorStats{PatternStats: PatternStats{ID: id}, CBadBy: map[string]int64{}, BadBy: map[string]int64{}}
+}
+
+// Add adds a value and the IDs of the gates that caused it to be "bad" to the
+// running tally.
+func (cs *CombinatorStats) Add(v bool, culprits []string) {
+	cs.PatternStats.Add(v)
+
+	for _, id := range culprits {
+		cs.CBadBy[id]++
+		cs.BadBy[id]++
+	}
+}
+
+// JSON returns a summary of the current combinator statistics and resets the
+// slot tally.
+func (cs *CombinatorStats) JSON() string {
+	out, _ := json.Marshal(cs)
+	cs.Total = 0
+	cs.GoodCount = 0
+	cs.BadCount = 0
+	cs.Ratio = 0
+	cs.BadBy = map[string]int64{}
+	return string(out)
+
+}
+
+// Next advances every gate and combines their values.
+func (fc *Combinator) Next() {
+	for _, g := range fc.gates {
+		g.Next()
+	}
+
+	fc.combine()
+}
+
+// combine calculates the value from the current values of the gates and
+// works out which gates caused it to be "bad".
+func (fc *Combinator) combine() {
+	good := 0
+	for _, g := range fc.gates {
+		if g.Good() {
+			good++
+		}
+	}
+
+	switch fc.op {
+	case "and", "or", "atLeast", "majority":
+		fc.v = good >= fc.k
+	case "xor":
+		fc.v = good%2 == 1
+	case "not":
+		fc.v = good == 0
+	}
+
+	fc.culprits = fc.culprits[:0]
+	if !fc.v {
+		for _, g := range fc.gates {
+			// Flipping any gate changes an exclusive or and a not is only bad
+			// because of a good gate, every other combinator because of the
+			// bad ones.
+			if fc.op == "xor" || g.Good() == (fc.op == "not") {
+				fc.culprits = append(fc.culprits, g.ID())
+			}
+		}
+	}
+
+	if fc.keepStats {
+		fc.Stats.Add(fc.v, fc.culprits)
+	}
+}
+
+// Val returns the current value.
+func (fc *Combinator) Val() interface{} {
+	return fc.v
+}
+
+// Vals returns the next count of values as an interface{} array.
+func (fc *Combinator) Vals(count int) []interface{} {
+	return makeValues(fc, count)
+}
+
+// JSONStats retrieves the current stats as s JSON string.
+func (fc *Combinator) JSONStats() string {
+	return fc.Stats.JSON()
+}
+
+// ID returns the unique id.
+func (fc *Combinator) ID() string {
+	return fc.id
+}
+
+// Named changes the id of the combinator from the one derived from its gates,
+// e.g. "and(network,collector)", and returns it.
+func (fc *Combinator) Named(id string) *Combinator {
+	fc.id = id
+	fc.Stats.ID = id
+	return fc
+}
+
+// WithStats makes the combinator keep internal statistics, starting with the
+// current value, and returns it.
+func (fc *Combinator) WithStats() *Combinator {
+	if !fc.keepStats {
+		fc.keepStats = true
+		fc.Stats.Add(fc.v, fc.culprits)
+	}
+
+	return fc
+}
+
+// Gates returns the gates that are combined.
+func (fc *Combinator) Gates() []Gate {
+	return fc.gates
+}
+
+// Culprits returns the IDs of the gates that caused the current value to be
+// "bad" or nothing if it is "good".
+func (fc *Combinator) Culprits() []string {
+	return append([]string(nil), fc.culprits...)
+}
+
+// Good returns whether the current value is "good".
+func (fc *Combinator) Good() bool {
+	return fc.v
+}
+
+// Bad returns whether the current value is "bad".
+func (fc *Combinator) Bad() bool {
+	return !fc.v
+}
+
+// Values returns the next count of values as a bool array.
+func (fc *Combinator) Values(count int) []bool {
+	out := make([]bool, count)
+	fc.Fill(out)
+	return out
+}
+
+// Step returns the current value and generates the next one.
+func (fc *Combinator) Step() bool {
+	out := fc.Good()
+	fc.Next()
+	return out
+}
+
+// Fill writes the next len(dst) values into dst without allocating and
+// returns how many were written.
+func (fc *Combinator) Fill(dst []bool) int {
+	for i := range dst {
+		dst[i] = fc.Good()
+		fc.Next()
+	}
+
+	return len(dst)
+}
+
+// combinatorState is the runtime state of a Combinator saved by Snapshot().
+type combinatorState struct {
+	ID       string            `json:"id"`
+	V        bool              `json:"v"`
+	Culprits []string          `json:"culprits"`
+	Stats    CombinatorStats   `json:"stats"`
+	Gates    []json.RawMessage `json:"gates"`
+}
+
+// Snapshot saves the runtime state of the combinator, including the state of
+// its gates, so it can be restored later with Restore().
+func (fc *Combinator) Snapshot() ([]byte, error) {
+	st := combinatorState{ID: fc.id, V: fc.v, Culprits: fc.culprits, Stats: *fc.Stats, Gates: make([]json.RawMessage, len(fc.gates))}
+
+	for i, g := range fc.gates {
+		b, err := g.Snapshot()
+		if err != nil {
+			return nil, err
+		}
+		st.Gates[i] = b
+	}
+
+	return json.Marshal(st)
+}
+
+// Restore restores the runtime state saved by Snapshot(). The combinator must
+// have been created with the same gates as the one that was saved and will
+// continue with the identical sequence.
+func (fc *Combinator) Restore(b []byte) error {
+	var st combinatorState
+	if err := json.Unmarshal(b, &st); err != nil {
+		return err
+	}
+
+	if st.ID != fc.id {
+		return errors.New("cannot restore a fake combinator with id '" + fc.id + "' from a snapshot of id '" + st.ID + "'")
+	}
+
+	if len(st.Gates) != len(fc.gates) {
+		return errors.New("cannot restore a fake combinator with id '" + fc.id + "' of " + fmt.Sprintf("%v", len(fc.gates)) + " gates from a snapshot of " + fmt.Sprintf("%v", len(st.Gates)) + " gates")
+	}
+
+	for i, g := range fc.gates {
+		if err := g.Restore(st.Gates[i]); err != nil {
+			return err
+		}
+	}
+
+	fc.v = st.V
+	fc.culprits = append(fc.culprits[:0], st.Culprits...)
+	*fc.Stats = st.Stats
+	return nil
+}
+
+// newCombinator creates a combinator of the gates that is good when at least
+// k of them are good, except for "xor" and "not". The gates are already at
+// their first value so they are combined without advancing them.
+func newCombinator(op string, k int, gates []Gate) *Combinator {
+	ids := make([]string, len(gates))
+	for i, g := range gates {
+		ids[i] = g.ID()
+	}
+
+	id := op + "(" + strings.Join(ids, ",") + ")"
+	if op == "atLeast" {
+		id = op + "(" + fmt.Sprintf("%v", k) + "," + strings.Join(ids, ",") + ")"
+	}
+
+	c := &Combinator{
+		id:    id,
+		op:    op,
+		k:     k,
+		gates: gates,
+		Stats: NewCombinatorStats(id),
+	}
+
+	c.combine()
+	return c
+}
+
+// And creates a combinator that is "good" when every gate is "good". The
+// gates that are "bad" cause a "bad" value.
+func And(a Gate, b ...Gate) *Combinator {
+	gates := append([]Gate{a}, b...)
+	return newCombinator("and", len(gates), gates)
+}
+
+// Or creates a combinator that is "good" when any gate is "good", e.g. a
+// sample that is collected by two redundant collectors. It is only "bad"
+// when every gate is, which then causes it.
+func Or(a Gate, b ...Gate) *Combinator {
+	return newCombinator("or", 1, append([]Gate{a}, b...))
+}
+
+// Xor creates a combinator that is "good" when an odd number of gates is
+// "good". Every gate causes a "bad" value.
+func Xor(a Gate, b ...Gate) *Combinator {
+	return newCombinator("xor", 0, append([]Gate{a}, b...))
+}
+
+// Not creates a combinator that is "good" when the gate is "bad", e.g. to
+// turn a Pattern of maintenance windows into the samples that are lost.
+func Not(g Gate) *Combinator {
+	return newCombinator("not", 0, []Gate{g})
+}
+
+// AtLeast creates a combinator that is "good" when at least k gates are
+// "good", e.g. a quorum of replicas. It is always "good" for a k of 0 or less
+// and always "bad" for a k greater than the number of gates. The gates that
+// are "bad" cause a "bad" value.
+func AtLeast(k int, gates ...Gate) *Combinator {
+	return newCombinator("atLeast", k, append([]Gate(nil), gates...))
+}
+
+// Majority creates a combinator that is "good" when more than half of the
+// gates are "good". The gates that are "bad" cause a "bad" value.
+func Majority(gates ...Gate) *Combinator {
+	return newCombinator("majority", len(gates)/2+1, append([]Gate(nil), gates...))
+}
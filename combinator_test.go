@@ -0,0 +1,154 @@
+package fake
+
+import (
+	"fmt"
+	"strings"
+)
+
+// dots draws good values as '.' and bad values as 'x'.
+func dots(values []bool) string {
+	var sb strings.Builder
+	for _, good := range values {
+		if good {
+			sb.WriteString(".")
+		} else {
+			sb.WriteString("x")
+		}
+	}
+	return sb.String()
+}
+
+func ExampleAnd() {
+	network, _ := NewPattern("network", 4, 2, true)
+	collector, _ := NewPattern("collector", 2, 1, true)
+	fc := And(network, collector)
+
+	fmt.Println(fc.ID())
+	for i := 0; i < 6; i++ {
+		fmt.Println(fc.Good(), fc.Culprits())
+		fc.Next()
+	}
+	// Output:
+	// and(network,collector)
+	// true []
+	// true []
+	// false [collector]
+	// true []
+	// false [network]
+	// false [network collector]
+}
+
+func ExampleOr() {
+	primary, _ := NewPattern("primary", 2, 2, true)
+	secondary, _ := NewPattern("secondary", 3, 1, true)
+
+	fmt.Println(dots(primary.Values(12)))
+	fmt.Println(dots(secondary.Values(12)))
+
+	primary, _ = NewPattern("primary", 2, 2, true)
+	secondary, _ = NewPattern("secondary", 3, 1, true)
+	fmt.Println(dots(Or(primary, secondary).Values(12)))
+	// Output:
+	// ..xx..xx..xx
+	// ...x...x...x
+	// ...x...x...x
+}
+
+func ExampleNot() {
+	maintenance, _ := NewPattern("maintenance", 3, 1, true)
+	fc := Not(maintenance).Named("lost").WithStats()
+
+	fmt.Println(dots(fc.Values(8)))
+	fmt.Println(fc.JSONStats())
+	// Output:
+	// xxx.xxx.
+	// {"id":"lost","cumulativeTotal":9,"cumulativeGoodCount":2,"cumulativeBadCount":7,"cumulativeRatio":0.2222222222222222,"slotTotal":9,"slotGoodCount":2,"slotBadCount":7,"slotGoodRatio":0.2222222222222222,"cumulativeBadBy":{"maintenance":7},"slotBadBy":{"maintenance":7}}
+}
+
+func ExampleXor() {
+	a, _ := NewPattern("a", 1, 1, true)
+	b, _ := NewPattern("b", 2, 2, true)
+	fmt.Println(dots(Xor(a, b).Values(8)))
+	// Output:
+	// x..xx..x
+}
+
+func ExampleAtLeast() {
+	var replicas []Gate
+	for i := 0; i < 3; i++ {
+		r, _ := NewRandom("replica-"+fmt.Sprintf("%v", i), int64(i), 0.8, true)
+		replicas = append(replicas, r)
+	}
+
+	fc := AtLeast(2, replicas...).WithStats()
+	fc.Values(100000)
+	fmt.Printf("%v %.3f\n", fc.ID(), fc.Stats.CRatio)
+	fmt.Println(fc.Stats.CBadBy)
+	// Output:
+	// atLeast(2,replica-0,replica-1,replica-2) 0.897
+	// map[replica-0:7160 replica-1:7142 replica-2:7161]
+}
+
+func ExampleMajority() {
+	var replicas []Gate
+	for i := 0; i < 3; i++ {
+		r, _ := NewRandom("replica-"+fmt.Sprintf("%v", i), int64(i), 0.8, true)
+		replicas = append(replicas, r)
+	}
+
+	fc := Majority(replicas...).WithStats()
+	fc.Values(100000)
+	fmt.Printf("%v %.3f\n", fc.ID(), fc.Stats.CRatio)
+	// Output:
+	// majority(replica-0,replica-1,replica-2) 0.897
+}
+
+// Combinators combine other combinators.
+func ExampleCombinator_nested() {
+	network, _ := NewBurstRandom("network", 1, 0.95, 5, true)
+	collector, _ := NewRandom("collector", 2, 0.99, true)
+	backup, _ := NewPattern("backup", 23, 1, true)
+	fc := And(Or(network, collector).Named("ingest"), backup).Named("outage").WithStats()
+
+	fmt.Println(dots(fc.Values(48)))
+	fc.Values(100000)
+	fmt.Printf("%.3f %v\n", fc.Stats.CRatio, fc.Stats.CBadBy)
+	// Output:
+	// .......................x.......................x
+	// 0.958 map[backup:4168 ingest:53]
+}
+
+func ExampleCombinator_Snapshot() {
+	network, _ := NewRandom("network", 1, 0.7, true)
+	backup, _ := NewPattern("backup", 5, 1, true)
+	fc1 := And(network, backup)
+	fc1.Values(50)
+	snapshot, _ := fc1.Snapshot()
+
+	network, _ = NewRandom("network", 1, 0.7, true)
+	backup, _ = NewPattern("backup", 5, 1, true)
+	fc2 := And(network, backup)
+	fc2.Restore(snapshot)
+	fmt.Println(dots(fc2.Values(20)))
+	fmt.Println(dots(fc1.Values(20)))
+	// Output:
+	// x.xxx....xxxxx.xx.xx
+	// x.xxx....xxxxx.xx.xx
+}
+
+func ExampleCombinator_WithStats() {
+	network, _ := NewPattern("network", 4, 2, false)
+	collector, _ := NewPattern("collector", 2, 1, false)
+	fc := And(network, collector)
+	fc.Values(6)
+	fmt.Println(fc.Stats.CTotal, fc.Stats.CBadBy)
+
+	network, _ = NewPattern("network", 4, 2, false)
+	collector, _ = NewPattern("collector", 2, 1, false)
+	fc = And(network, collector).WithStats()
+	fc.Values(6)
+	fmt.Println(fc.Stats.CTotal, fc.Stats.CBadBy)
+	// Output:
+	// 0 map[]
+	// 7 map[collector:2 network:2]
+}
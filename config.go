@@ -0,0 +1,73 @@
+package fake
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FieldError describes a single invalid field of a configuration.
+type FieldError struct {
+	// The name of the offending field, e.g. "Samples".
+	Field string
+
+	// The value the field had when it was validated.
+	Value interface{}
+
+	// Why the value is invalid, e.g. "must be greater than 0".
+	Reason string
+}
+
+// Error returns the field error as a human readable string.
+func (fe *FieldError) Error() string {
+	return fe.Field + " " + fe.Reason + " but was '" + fmt.Sprintf("%v", fe.Value) + "'"
+}
+
+// ConfigError is returned when validating a configuration fails. It holds
+// every FieldError found rather than just the first one so all problems can be
+// fixed in one go.
+type ConfigError struct {
+	// The kind of configuration, e.g. "data".
+	Kind string
+
+	// The ID of the configuration.
+	ID string
+
+	// All invalid fields in the order they were checked.
+	Fields []*FieldError
+}
+
+// Error returns all field errors as a single human readable string.
+func (ce *ConfigError) Error() string {
+	msgs := make([]string, len(ce.Fields))
+	for i, fe := range ce.Fields {
+		msgs[i] = fe.Error()
+	}
+
+	return "invalid fake " + ce.Kind + " with id '" + ce.ID + "': " + strings.Join(msgs, "; ")
+}
+
+// Field returns the FieldError for the named field or nil if the field is
+// valid.
+func (ce *ConfigError) Field(name string) *FieldError {
+	for _, fe := range ce.Fields {
+		if fe.Field == name {
+			return fe
+		}
+	}
+
+	return nil
+}
+
+// add records a new invalid field.
+func (ce *ConfigError) add(field string, value interface{}, reason string) {
+	ce.Fields = append(ce.Fields, &FieldError{Field: field, Value: value, Reason: reason})
+}
+
+// err returns the ConfigError as an error or nil when no fields are invalid.
+func (ce *ConfigError) err() error {
+	if len(ce.Fields) == 0 {
+		return nil
+	}
+
+	return ce
+}
@@ -0,0 +1,365 @@
+package fake
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// Counter generates the values of a monotonically increasing counter, e.g.
+// http_requests_total or the bytes received by an interface. Every sample
+// the counter goes up by the value of a rate Data (negative rates count as
+// 0). Like real counters it can be reset to 0 (e.g. by a process restart)
+// and wrap around when it outgrows its width.
+//
+// The counter advances its rate Data and its reset Gate itself so neither
+// should be advanced by anything else.
+type Counter struct {
+	id        string
+	rate      *Data
+	start     float64
+	resets    Gate
+	resetAt   []int64
+	wrapBits  uint
+	wrap      float64
+	i         int64
+	v         float64
+	reset     bool
+	wrapped   bool
+	keepStats bool
+	Stats     *CounterStats
+}
+
+// CounterStats keeps track of various statistics of a Counter while it's
+// running. The increase is derived from consecutive values the way a query
+// (e.g. PromQL's rate() and increase()) derives it: the difference between
+// two values or, when the value dropped, the new value.
+type CounterStats struct {
+
+	// The ID of the Counter.
+	ID string `json:"id"`
+
+	// Cumulative count of how many times Next() was called.
+	CTotal int64 `json:"cumulativeTotal"`
+
+	// Cumulative count of resets.
+	CResets int64 `json:"cumulativeResets"`
+
+	// Cumulative count of wraparounds.
+	CWraps int64 `json:"cumulativeWraps"`
+
+	// Cumulative increase derived from the values.
+	CIncrease float64 `json:"cumulativeIncrease"`
+
+	// Cumulative increase per sample derived from the values.
+	CRate float64 `json:"cumulativeRate"`
+
+	// Slot count of how many times Next() was called. This gets reset after every JSON() call.
+	Total int64 `json:"slotTotal"`
+
+	// Slot count of resets. This gets reset after every JSON() call.
+	Resets int64 `json:"slotResets"`
+
+	// Slot count of wraparounds. This gets reset after every JSON() call.
+	Wraps int64 `json:"slotWraps"`
+
+	// Slot increase derived from the values. This gets reset after every JSON() call.
+	Increase float64 `json:"slotIncrease"`
+
+	// Slot increase per sample derived from the values. This gets reset after every JSON() call.
+	Rate float64 `json:"slotRate"`
+
+	// The previous value, to derive the next increase from.
+	Last float64 `json:"last"`
+}
+
+// Add adds a value to the running tally. Whether the counter was reset or
+// wrapped around is only counted, the increase is derived from the values.
+func (cs *CounterStats) Add(v float64, reset bool, wrapped bool) {
+	if cs.CTotal > 0 {
+		increase := v - cs.Last
+		if v < cs.Last {
+			increase = v
+		}
+
+		cs.CIncrease += increase
+		cs.Increase += increase
+	}
+
+	cs.Last = v
+	cs.CTotal++
+	cs.Total++
+
+	if reset {
+		cs.CResets++
+		cs.Resets++
+	}
+
+	if wrapped {
+		cs.CWraps++
+		cs.Wraps++
+	}
+
+	cs.CRate = cs.CIncrease / float64(cs.CTotal)
+	cs.Rate = cs.Increase / float64(cs.Total)
+}
+
+// JSON returns a summary of the current counter statistics and resets the
+// slot tally.
+func (cs *CounterStats) JSON() string {
+	out, _ := json.Marshal(cs)
+	cs.Total = 0
+	cs.Resets = 0
+	cs.Wraps = 0
+	cs.Increase = 0
+	cs.Rate = 0
+	return string(out)
+
+}
+
+// Next generates the next counter value.
+func (fc *Counter) Next() {
+	// The rate and resets start at their first value
+	if fc.i > 0 {
+		fc.rate.Next()
+		if fc.resets != nil {
+			fc.resets.Next()
+		}
+	}
+
+	increase := math.Max(fc.rate.Float(), 0)
+
+	fc.reset = fc.resets != nil && fc.resets.Bad()
+	for len(fc.resetAt) > 0 && fc.resetAt[0] <= fc.i {
+		fc.reset = fc.reset || fc.resetAt[0] == fc.i
+		fc.resetAt = fc.resetAt[1:]
+	}
+
+	if fc.i == 0 {
+		fc.v = fc.start
+	}
+
+	if fc.reset {
+		fc.v = 0
+	}
+
+	fc.v += increase
+
+	fc.wrapped = fc.wrap > 0 && fc.v >= fc.wrap
+	if fc.wrapped {
+		fc.v = math.Mod(fc.v, fc.wrap)
+	}
+
+	fc.i++
+
+	if fc.keepStats {
+		fc.Stats.Add(fc.v, fc.reset, fc.wrapped)
+	}
+}
+
+// Val returns the current counter value.
+func (fc *Counter) Val() interface{} {
+	return fc.v
+}
+
+// Vals returns the next count of values as an interface{} array.
+func (fc *Counter) Vals(count int) []interface{} {
+	return makeValues(fc, count)
+}
+
+// JSONStats retrieves the current stats as s JSON string.
+func (fc *Counter) JSONStats() string {
+	return fc.Stats.JSON()
+}
+
+// ID returns the unique id.
+func (fc *Counter) ID() string {
+	return fc.id
+}
+
+// Float returns the current counter value.
+func (fc *Counter) Float() float64 {
+	return fc.v
+}
+
+// Reset returns whether the counter was reset at the current value.
+func (fc *Counter) Reset() bool {
+	return fc.reset
+}
+
+// Wrapped returns whether the counter wrapped around at the current value.
+func (fc *Counter) Wrapped() bool {
+	return fc.wrapped
+}
+
+// Floats returns the next count of values as a float64 array.
+func (fc *Counter) Floats(count int) []float64 {
+	out := make([]float64, count)
+	fc.Fill(out)
+	return out
+}
+
+// Step returns the current value and generates the next one.
+func (fc *Counter) Step() float64 {
+	out := fc.v
+	fc.Next()
+	return out
+}
+
+// Fill writes the next len(dst) values into dst without allocating and
+// returns how many were written.
+func (fc *Counter) Fill(dst []float64) int {
+	for i := range dst {
+		dst[i] = fc.v
+		fc.Next()
+	}
+
+	return len(dst)
+}
+
+// counterState is the runtime state of a Counter saved by Snapshot().
+type counterState struct {
+	ID      string          `json:"id"`
+	I       int64           `json:"i"`
+	V       float64         `json:"v"`
+	Reset   bool            `json:"reset"`
+	Wrapped bool            `json:"wrapped"`
+	ResetAt []int64         `json:"resetAt"`
+	Stats   CounterStats    `json:"stats"`
+	Rate    json.RawMessage `json:"rate"`
+	Resets  json.RawMessage `json:"resets,omitempty"`
+}
+
+// Snapshot saves the runtime state of the counter, including the state of
+// its rate and resets, so it can be restored later with Restore().
+func (fc *Counter) Snapshot() ([]byte, error) {
+	st := counterState{ID: fc.id, I: fc.i, V: fc.v, Reset: fc.reset, Wrapped: fc.wrapped, ResetAt: fc.resetAt, Stats: *fc.Stats}
+
+	var err error
+	if st.Rate, err = fc.rate.Snapshot(); err != nil {
+		return nil, err
+	}
+
+	if fc.resets != nil {
+		if st.Resets, err = fc.resets.Snapshot(); err != nil {
+			return nil, err
+		}
+	}
+
+	return json.Marshal(st)
+}
+
+// Restore restores the runtime state saved by Snapshot(). The counter must
+// have been created with the same parameters as the one that was saved and
+// will continue with the identical sequence.
+func (fc *Counter) Restore(b []byte) error {
+	var st counterState
+	if err := json.Unmarshal(b, &st); err != nil {
+		return err
+	}
+
+	if st.ID != fc.id {
+		return errors.New("cannot restore a fake counter with id '" + fc.id + "' from a snapshot of id '" + st.ID + "'")
+	}
+
+	if err := fc.rate.Restore(st.Rate); err != nil {
+		return err
+	}
+
+	if fc.resets != nil {
+		if err := fc.resets.Restore(st.Resets); err != nil {
+			return err
+		}
+	}
+
+	fc.i = st.I
+	fc.v = st.V
+	fc.reset = st.Reset
+	fc.wrapped = st.Wrapped
+	fc.resetAt = st.ResetAt
+	*fc.Stats = st.Stats
+	return nil
+}
+
+// CounterOption changes a Counter before its first value is generated.
+type CounterOption func(*Counter)
+
+// WithStart sets the value the counter starts from instead of 0, e.g. for a
+// process that has been running for a while.
+func WithStart(v float64) CounterOption {
+	return func(fc *Counter) {
+		fc.start = v
+	}
+}
+
+// WithResets resets the counter whenever the gate is "bad", e.g. a Random
+// that is good 99.9% of the time or a Pattern resetting every day.
+func WithResets(g Gate) CounterOption {
+	return func(fc *Counter) {
+		fc.resets = g
+	}
+}
+
+// WithResetsAt resets the counter at the given samples (the first sample
+// being 0).
+func WithResetsAt(samples ...int64) CounterOption {
+	return func(fc *Counter) {
+		fc.resetAt = append(fc.resetAt, samples...)
+	}
+}
+
+// WithWrap wraps the counter around to 0 when it reaches 2^bits, e.g. 32 for
+// the 32-bit interface counters of SNMP. 0 never wraps.
+func WithWrap(bits uint) CounterOption {
+	return func(fc *Counter) {
+		fc.wrapBits = bits
+	}
+}
+
+// NewCounter creates a new counter. A counter has a unique id, a rate Data
+// which it goes up by every sample and needs to know wheter to keep internal
+// statistics. Resets, wraparounds and a start value are set with options.
+func NewCounter(id string, rate *Data, keepStats bool, opts ...CounterOption) (*Counter, error) {
+	if id == "" {
+		return nil, errors.New("ID for a fake counter cannot be blank")
+	}
+
+	if rate == nil {
+		return nil, errors.New("rate for a fake counter with id '" + id + "' cannot be nil")
+	}
+
+	c := &Counter{
+		id:        id,
+		rate:      rate,
+		keepStats: keepStats,
+		Stats:     &CounterStats{ID: id},
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	if c.wrapBits > 64 {
+		return nil, errors.New("wrap of a fake counter with id '" + id + "' cannot be more than 64 bits but was '" + fmt.Sprintf("%v", c.wrapBits) + "'")
+	}
+
+	if c.wrapBits > 0 {
+		c.wrap = math.Ldexp(1, int(c.wrapBits))
+	}
+
+	if c.start < 0 {
+		return nil, errors.New("start of a fake counter with id '" + id + "' cannot be less than 0 but was '" + fmt.Sprintf("%v", c.start) + "'")
+	}
+
+	if c.wrap > 0 && c.start >= c.wrap {
+		return nil, errors.New("start of a fake counter with id '" + id + "' must be less than its wrap of '" + fmt.Sprintf("%v", c.wrap) + "'")
+	}
+
+	c.resetAt = append([]int64(nil), c.resetAt...)
+	sort.Slice(c.resetAt, func(a, b int) bool { return c.resetAt[a] < c.resetAt[b] })
+
+	c.Next()
+	return c, nil
+}
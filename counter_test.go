@@ -0,0 +1,63 @@
+package fake
+
+import (
+	"fmt"
+)
+
+func ExampleNewCounter() {
+	// 10 requests per sample
+	rate, _ := NewDataFromConfig(NewDataConfig("rate", 10, WithRange(0, 20)))
+	fc, _ := NewCounter("requests", rate, true, WithStart(1000), WithResetsAt(4))
+
+	fmt.Println(fc.Floats(8))
+	fmt.Println(fc.Stats.JSON())
+	// Output:
+	// [1010 1020 1030 1040 10 20 30 40]
+	// {"id":"requests","cumulativeTotal":9,"cumulativeResets":1,"cumulativeWraps":0,"cumulativeIncrease":80,"cumulativeRate":8.88888888888889,"slotTotal":9,"slotResets":1,"slotWraps":0,"slotIncrease":80,"slotRate":8.88888888888889,"last":50}
+}
+
+func ExampleWithWrap() {
+	rate, _ := NewDataFromConfig(NewDataConfig("rate", 10, WithRange(0, 20)))
+	fc, _ := NewCounter("bytes", rate, true, WithWrap(5))
+
+	for i := 0; i < 5; i++ {
+		fmt.Println(fc.Float(), fc.Wrapped())
+		fc.Next()
+	}
+	fmt.Println(fc.Stats.CWraps, fc.Stats.CIncrease)
+	// Output:
+	// 10 false
+	// 20 false
+	// 30 false
+	// 8 true
+	// 18 false
+	// 1 48
+}
+
+func ExampleWithResets() {
+	rate, _ := NewDataFromConfig(NewDataConfig("rate", 1000, WithRange(0, 20), WithBump(20), WithRandom(1, 0.5)))
+	restarts, _ := NewPattern("restarts", 4, 1, false)
+	fc, _ := NewCounter("requests", rate, true, WithResets(restarts))
+
+	fmt.Printf("%.1f\n", fc.Floats(10))
+	fmt.Println(fc.Stats.CResets)
+	// Output:
+	// [10.8 21.3 31.8 42.3 10.5 21.0 31.7 42.5 53.6 11.1]
+	// 2
+}
+
+func ExampleCounter_Snapshot() {
+	rate1, _ := NewDataFromConfig(NewDataConfig("rate", 100, WithRange(0, 20), WithBump(20), WithRandom(1, 0.5)))
+	fc1, _ := NewCounter("requests", rate1, true, WithResetsAt(7))
+	fc1.Floats(5)
+	snapshot, _ := fc1.Snapshot()
+
+	rate2, _ := NewDataFromConfig(NewDataConfig("rate", 100, WithRange(0, 20), WithBump(20), WithRandom(1, 0.5)))
+	fc2, _ := NewCounter("requests", rate2, true, WithResetsAt(7))
+	fc2.Restore(snapshot)
+	fmt.Printf("%.1f\n", fc2.Floats(5))
+	fmt.Printf("%.1f\n", fc1.Floats(5))
+	// Output:
+	// [64.9 76.0 11.3 22.9 34.6]
+	// [64.9 76.0 11.3 22.9 34.6]
+}
@@ -0,0 +1,263 @@
+// Package csvout writes fake time series as CSV.
+//
+// A Writer takes a Time and a set of named columns and writes one row per
+// sample:
+//
+//  w := csvout.NewWriter(os.Stdout, fakeTime,
+//      csvout.Column{Name: "CPU", Value: fakeData1, Gates: []fake.Gate{fakeRandom}},
+//      csvout.Column{Name: "Memory", Value: fakeData2})
+//  w.Gates = []fake.Gate{fakePattern}
+//  w.Precision = 1
+//  err := w.Write(1000)
+//
+// which may look as follows:
+//
+//  Timestamp,CPU,Memory
+//  2020-02-07T01:39:10Z,23.5,97.2
+//  2020-02-07T01:40:50Z,,84.9
+//  ...etc...
+//
+// How "bad samples" (a row gate is bad) and "bad data" (a column gate is bad)
+// are rendered is configurable.
+package csvout
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"time"
+
+	fake "github.com/powerpu/go-fake-ts"
+)
+
+// TimeFormat is how timestamps are written.
+type TimeFormat int
+
+const (
+	// RFC3339 writes timestamps such as 2020-02-07T01:39:10Z.
+	RFC3339 TimeFormat = iota
+
+	// EpochSeconds writes the number of seconds since 1970-01-01 UTC.
+	EpochSeconds
+
+	// EpochMillis writes the number of milliseconds since 1970-01-01 UTC.
+	EpochMillis
+
+	// EpochNanos writes the number of nanoseconds since 1970-01-01 UTC.
+	EpochNanos
+)
+
+// Bad is how a bad sample or bad data is rendered.
+type Bad int
+
+const (
+	// SkipRow leaves out the whole row.
+	SkipRow Bad = iota
+
+	// EmptyCell writes an empty cell.
+	EmptyCell
+
+	// NaN writes NaN.
+	NaN
+
+	// Sentinel writes the Sentinel of the Writer.
+	Sentinel
+)
+
+// Column is a single named column of a Writer.
+type Column struct {
+	// The name of the column in the header.
+	Name string
+
+	// The value of the column, usually a Data but any Value will do.
+	Value fake.Value
+
+	// Decide whether the data of the column is "good". Optional.
+	Gates []fake.Gate
+}
+
+// Writer writes a Time and a set of columns as CSV. Every call to Write
+// advances the Time, the gates and all columns in lock-step so none of them
+// may appear twice or be used elsewhere while writing.
+//
+// A Writer must be created with NewWriter, which sets the defaults of the
+// fields below. The zero value has nowhere to write to.
+type Writer struct {
+	// The timestamp of every row.
+	Time *fake.Time
+
+	// Decide whether a sample is "good". Optional.
+	Gates []fake.Gate
+
+	// The columns written after the timestamp.
+	Columns []Column
+
+	// Whether to write a header row before the first row. NewWriter sets true.
+	Header bool
+
+	// The header of the timestamp column. NewWriter sets "Timestamp".
+	TimeHeader string
+
+	// How timestamps are written. NewWriter sets RFC3339.
+	TimeFormat TimeFormat
+
+	// The number of digits after the decimal point of float values. A
+	// negative precision uses the smallest number of digits necessary to
+	// represent the value exactly. NewWriter sets -1.
+	Precision int
+
+	// How bad samples are rendered. NewWriter sets SkipRow.
+	BadSample Bad
+
+	// How bad data is rendered. NewWriter sets EmptyCell.
+	BadData Bad
+
+	// What to write for Sentinel, e.g. "-1" or "N/A".
+	Sentinel string
+
+	// The field delimiter. NewWriter sets ','.
+	Comma rune
+
+	w             *csv.Writer
+	headerWritten bool
+}
+
+// NewWriter creates a Writer writing to w with all defaults set.
+func NewWriter(w io.Writer, ft *fake.Time, columns ...Column) *Writer {
+	return &Writer{
+		Time:       ft,
+		Columns:    columns,
+		Header:     true,
+		TimeHeader: "Timestamp",
+		TimeFormat: RFC3339,
+		Precision:  -1,
+		BadSample:  SkipRow,
+		BadData:    EmptyCell,
+		Comma:      ',',
+		w:          csv.NewWriter(w),
+	}
+}
+
+// Write writes the next count samples and flushes them to the underlying
+// io.Writer. Skipped rows are counted too.
+func (cw *Writer) Write(count int) error {
+	if cw.w == nil {
+		return errors.New("csvout: a Writer must be created with NewWriter")
+	}
+
+	cw.w.Comma = cw.Comma
+
+	if cw.Header && !cw.headerWritten {
+		header := make([]string, 0, len(cw.Columns)+1)
+		header = append(header, cw.TimeHeader)
+		for _, c := range cw.Columns {
+			header = append(header, c.Name)
+		}
+
+		if err := cw.w.Write(header); err != nil {
+			return err
+		}
+	}
+	cw.headerWritten = true
+
+	record := make([]string, len(cw.Columns)+1)
+	for i := 0; i < count; i++ {
+		if cw.row(record) {
+			if err := cw.w.Write(record); err != nil {
+				return err
+			}
+		}
+
+		cw.next()
+	}
+
+	cw.w.Flush()
+	return cw.w.Error()
+}
+
+// row renders the current sample into record and returns whether it should
+// be written.
+func (cw *Writer) row(record []string) bool {
+	record[0] = cw.format(cw.Time.Time())
+
+	if !fake.AllGood(cw.Gates...) {
+		if cw.BadSample == SkipRow {
+			return false
+		}
+
+		for i := range cw.Columns {
+			record[i+1] = cw.bad(cw.BadSample)
+		}
+
+		return true
+	}
+
+	for i, c := range cw.Columns {
+		if !fake.AllGood(c.Gates...) {
+			if cw.BadData == SkipRow {
+				return false
+			}
+
+			record[i+1] = cw.bad(cw.BadData)
+			continue
+		}
+
+		record[i+1] = cw.format(c.Value.Val())
+	}
+
+	return true
+}
+
+// next advances every generator by one sample.
+func (cw *Writer) next() {
+	cw.Time.Next()
+
+	for _, g := range cw.Gates {
+		g.Next()
+	}
+
+	for _, c := range cw.Columns {
+		c.Value.Next()
+		for _, g := range c.Gates {
+			g.Next()
+		}
+	}
+}
+
+// format renders a single value.
+func (cw *Writer) format(v interface{}) string {
+	switch v := v.(type) {
+	case float64:
+		return strconv.FormatFloat(v, 'f', cw.Precision, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	case time.Time:
+		switch cw.TimeFormat {
+		case EpochSeconds:
+			return strconv.FormatInt(v.Unix(), 10)
+		case EpochMillis:
+			return strconv.FormatInt(v.UnixMilli(), 10)
+		case EpochNanos:
+			return strconv.FormatInt(v.UnixNano(), 10)
+		}
+
+		return v.Format(time.RFC3339)
+	}
+
+	return fmt.Sprint(v)
+}
+
+// bad renders a bad cell.
+func (cw *Writer) bad(b Bad) string {
+	switch b {
+	case NaN:
+		return strconv.FormatFloat(math.NaN(), 'f', -1, 64)
+	case Sentinel:
+		return cw.Sentinel
+	}
+
+	return ""
+}
@@ -0,0 +1,60 @@
+package csvout
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	fake "github.com/powerpu/go-fake-ts"
+)
+
+func ExampleWriter() {
+	ft, _ := fake.NewTime("ts", time.Date(2020, 2, 7, 0, 0, 0, 0, time.UTC), 3600000, 0, 0, false)
+	fp, _ := fake.NewPattern("nightly", 3, 1, false)
+	fr, _ := fake.NewRandom("cpuGate", 4, 0.7, false)
+	cpu, _ := fake.NewDataFromConfig(fake.NewDataConfig("cpu", 8, fake.WithRange(0, 100), fake.WithBump(80), fake.WithRandom(1, 0.5)))
+	mem, _ := fake.NewDataFromConfig(fake.NewDataConfig("memory", 8, fake.WithRange(0, 100), fake.WithSlope(1)))
+
+	w := NewWriter(os.Stdout, ft,
+		Column{Name: "CPU", Value: cpu, Gates: []fake.Gate{fr}},
+		Column{Name: "Memory", Value: mem})
+	w.Gates = []fake.Gate{fp}
+	w.Precision = 1
+	w.Write(8)
+	// Output:
+	// Timestamp,CPU,Memory
+	// 2020-02-07T00:00:00Z,43.1,50.0
+	// 2020-02-07T01:00:00Z,38.8,51.0
+	// 2020-02-07T02:00:00Z,38.2,52.0
+	// 2020-02-07T04:00:00Z,38.4,54.0
+	// 2020-02-07T05:00:00Z,37.7,55.0
+	// 2020-02-07T06:00:00Z,,56.0
+}
+
+func ExampleWriter_badSamples() {
+	ft, _ := fake.NewTime("ts", time.Date(2020, 2, 7, 0, 0, 0, 0, time.UTC), 60000, 0, 0, false)
+	fp, _ := fake.NewPattern("outage", 2, 1, false)
+	fd, _ := fake.NewDataFromConfig(fake.NewDataConfig("cpu", 6, fake.WithRange(0, 10), fake.WithSlope(1)))
+
+	w := NewWriter(os.Stdout, ft, Column{Name: "CPU", Value: fd})
+	w.Gates = []fake.Gate{fp}
+	w.TimeFormat = EpochMillis
+	w.BadSample = Sentinel
+	w.Sentinel = "-1"
+	w.Comma = ';'
+	w.Write(6)
+	// Output:
+	// Timestamp;CPU
+	// 1581033600000;5
+	// 1581033660000;6
+	// 1581033720000;-1
+	// 1581033780000;8
+	// 1581033840000;9
+	// 1581033900000;-1
+}
+
+func ExampleWriter_zero() {
+	var w Writer
+	fmt.Println(w.Write(1))
+	// Output: csvout: a Writer must be created with NewWriter
+}
@@ -2,9 +2,10 @@ package fake
 
 import (
 	"encoding/json"
-	"github.com/jhorwit2/simple-regression"
+	"errors"
+	"fmt"
 	"math"
-	"math/rand"
+	"sync"
 )
 
 // Data generates fake values based on desired parameters.
@@ -29,9 +30,10 @@ type Data struct {
 	permaBumpSmoother int64
 
 	// Random variables
-	useRandom bool
-	rnd       *rand.Rand
-	bias      float64
+	useRandom     bool
+	rnd           source
+	bias          float64
+	counterRandom bool
 
 	// Spike variables
 	spike             bool
@@ -42,6 +44,9 @@ type Data struct {
 	spikeWobbleFactor int64
 	spikeSmoother     int64
 
+	// The SpikeSmoother as configured, which schedules the first spike
+	spikeFirstSmoother int64
+
 	// Seasonality variables
 	seasonality      bool
 	seasonalityWave1 int64
@@ -62,6 +67,9 @@ type Data struct {
 	f          float64
 	i          int64
 	v          float64
+
+	// Steps of the random walk remembered by SeekTo() and At()
+	checkpoints *walkCheckpoints
 }
 
 // DataStats keeps track of various statistics of a Data while it's running.
@@ -105,9 +113,10 @@ type DataStats struct {
 	// Cumulative number of points at the "to" value (if applicable)
 	CPointsAtUpper int64 `json:"cumulativePointsAtUpperLimit"`
 
-	cRegression *linear.Regression
+	cRegression *regression
 
-	// Cumulative slope of the data
+	// Cumulative slope of the data. It is not a number (null in JSON) with
+	// fewer than two samples.
 	CSlope float64 `json:"cumulativeSlope"`
 
 	// Slot count of how many times Next() was called. This gets reset after every JSON() call.
@@ -136,12 +145,25 @@ type DataStats struct {
 	// Slot number of points at the "to" value (if applicable)
 	PointsAtUpper int64 `json:"slotPointsAtUpperLimit"`
 
-	regression *linear.Regression
+	regression *regression
 
-	// Slope of the current slot
+	// Slope of the current slot. It is not a number (null in JSON) with fewer
+	// than two samples.
 	Slope float64 `json:"slotSlope"`
 }
 
+// NewDataStats creates empty statistics for values that should stay between
+// from and to, e.g. to keep statistics of only part of a series.
+func NewDataStats(id string, from float64, to float64) *DataStats {
+	return &DataStats{
+		ID:          id,
+		From:        from,
+		To:          to,
+		regression:  newRegression(),
+		cRegression: newRegression(),
+	}
+}
+
 // Add adds a value to the running tally.
 func (ds *DataStats) Add(v float64) {
 
@@ -212,6 +234,25 @@ func (ds *DataStats) Add(v float64) {
 	}
 }
 
+// MarshalJSON encodes the statistics, reporting slopes that are not a number
+// as null.
+func (ds DataStats) MarshalJSON() ([]byte, error) {
+	type plain DataStats
+
+	slope := func(v float64) *float64 {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return nil
+		}
+		return &v
+	}
+
+	return json.Marshal(struct {
+		plain
+		CSlope *float64 `json:"cumulativeSlope"`
+		Slope  *float64 `json:"slotSlope"`
+	}{plain(ds), slope(ds.CSlope), slope(ds.Slope)})
+}
+
 // JSON returns a summary of the current pattern statistics and resets the slot
 // tally.
 func (ds *DataStats) JSON() string {
@@ -227,19 +268,26 @@ func (ds *DataStats) JSON() string {
 	ds.HitMaxAt = 0
 	ds.PointsMoreThan = 0
 	ds.PointsAtUpper = 0
-	ds.regression = linear.NewRegression()
+	ds.regression = newRegression()
 	ds.Slope = 0
 	return string(out)
 
 }
 
 func (fd *Data) calculateNextSpikeStartStop() {
-	fd.spikeStart = fd.spikeCount * (fd.spikeEvery - fd.spikeSmoother)
-	fd.spikeEnd = fd.spikeStart + (2 * fd.spikeSmoother) + fd.spikeSustain
+	// A SpikeSmoother of 0 is only raised to 1 once the first spike starts so
+	// the first spike is scheduled with 0, as it always has been
+	smoother := fd.spikeSmoother
+	if fd.spikeCount == 1 {
+		smoother = fd.spikeFirstSmoother
+	}
+
+	fd.spikeStart = fd.spikeCount * (fd.spikeEvery - smoother)
+	fd.spikeEnd = fd.spikeStart + (2 * smoother) + fd.spikeSustain
 
 	for i := (fd.spikeCount + 1); fd.spikeStart < fd.i; i++ {
-		fd.spikeStart = i * (fd.spikeEvery - fd.spikeSmoother)
-		fd.spikeEnd = fd.spikeStart + (2 * fd.spikeSmoother) + fd.spikeSustain
+		fd.spikeStart = i * (fd.spikeEvery - smoother)
+		fd.spikeEnd = fd.spikeStart + (2 * smoother) + fd.spikeSustain
 		if fd.spikeStart <= 0 {
 			break
 		}
@@ -248,44 +296,122 @@ func (fd *Data) calculateNextSpikeStartStop() {
 
 // Next generates the next fake number value.
 func (fd *Data) Next() {
+	fd.step()
+
+	if fd.keepStats {
+		fd.Stats.Add(fd.v)
+	}
+}
+
+// step generates the next fake number value without adding it to the
+// statistics.
+func (fd *Data) step() {
 	// We're at the end of a spike, let's calculate when the next spike starts
 	if fd.i == 0 || fd.i == fd.spikeEnd {
 		fd.spikeCount++
 		fd.calculateNextSpikeStartStop()
 	}
 
-	spread := math.Abs(fd.to) + math.Abs(fd.from)
-
 	// Column A
 	a := fd.rnd.Float64()
 
-	// Column E
-	e := (fd.from + fd.to) / 2
+	fd.b = fd.walk(fd.i, a, fd.b)
+	f := fd.base(fd.i, fd.b)
+	v := f
 
-	if fd.useRandom {
-		// Column B
-		if fd.i == 0 {
-			// fd.b = a * fd.to
-			fd.b = a
+	// Let's stretch or squish. We need to do it both up and down.
+	stv := fd.stretchStart + (float64(fd.i) * fd.stretchStep)
+	if stv > 1 { // Stretch above 1 means "stretch"
+		if f > fd.f { // Stretching on the way up
+			v = f + ((f - fd.f) * stv)
+		} else if f < fd.f { // Stretching on the way down
+			v = f - ((fd.f - f) * stv)
+		} else { // Flat value so just use whatever the previous value was
+			v = fd.v
 		}
+	} else if stv < 1 { // Stretch below 1 means "squish"
+		v = f * stv
+	}
+
+	// Let's do spikes!
+	if fd.spike && fd.i >= fd.spikeStart && !(fd.i > fd.spikeEnd) {
+		multiplier := int64(0)
+		spikeValue := (float64(fd.spikeTo) / 100) * fd.to
 
-		bPrev := fd.b
-		if fd.i > 0 {
-			fd.b = bPrev
+		if fd.i >= fd.spikeStart && fd.i < (fd.spikeStart+fd.spikeSmoother) { // Going up?
+			multiplier = fd.spikeSmoother - ((fd.spikeStart + fd.spikeSmoother) - fd.i) + 1
+		} else if fd.i > (fd.spikeEnd - fd.spikeSmoother) { // Going down?
+			multiplier = fd.spikeEnd - fd.i + 1
 		}
 
-		if fd.bias <= 0 {
-			fd.b = bPrev + fd.b
-		} else if fd.bias >= 1 {
-			fd.b = bPrev - fd.b
-		} else if a > fd.bias {
-			fd.b = bPrev + (((a - 0.5) * (a - 0.5)) * -1)
-		} else if a < fd.bias {
-			fd.b = bPrev + ((a - 0.5) * (a - 0.5))
+		if multiplier == 0 {
+			if fd.spikeWobble {
+				if fd.spikeWobbleFactor > 0 {
+					v = spikeValue - ((a * spikeValue) / float64(fd.spikeWobbleFactor))
+				} else {
+					v = spikeValue + ((a * spikeValue) / float64(fd.spikeWobbleFactor))
+				}
+			} else {
+				v = spikeValue
+			}
+		} else {
+			// Let's apply a smoother as we're wither going up or down from the peak
+			tmp := (float64(1) - math.Abs(1/(float64(multiplier)))) * (1 + (1 / float64(fd.spikeSmoother)))
+			v = v + (tmp * tmp * (spikeValue - v))
 		}
+	}
+
+	// Let's limit
+	if fd.limitLower && v < fd.from {
+		v = fd.from
+	} else if fd.limitUpper && v > fd.to {
+		v = fd.to
+	}
+
+	// Setup next iteration
+	fd.f = f
+	fd.i = fd.i + 1
+	fd.v = v
+}
+
+// walk takes the random walk (Column B) one step further for sample i using
+// the random number a and the previous step b.
+func (fd *Data) walk(i int64, a float64, b float64) float64 {
+	if !fd.useRandom {
+		return b
+	}
+
+	if i == 0 {
+		// b = a * fd.to
+		b = a
+	}
 
+	bPrev := b
+
+	if fd.bias <= 0 {
+		b = bPrev + b
+	} else if fd.bias >= 1 {
+		b = bPrev - b
+	} else if a > fd.bias {
+		b = bPrev + (((a - 0.5) * (a - 0.5)) * -1)
+	} else if a < fd.bias {
+		b = bPrev + ((a - 0.5) * (a - 0.5))
+	}
+
+	return b
+}
+
+// base calculates the value of sample i before it is stretched, spiked and
+// limited (Column F) using the random walk b of the same sample.
+func (fd *Data) base(i int64, b float64) float64 {
+	spread := math.Abs(fd.to) + math.Abs(fd.from)
+
+	// Column E
+	e := (fd.from + fd.to) / 2
+
+	if fd.useRandom {
 		// Column C
-		c := fd.b + (math.Log(float64(fd.samples)) / math.Log(2.5))
+		c := b + (math.Log(float64(fd.samples)) / math.Log(2.5))
 
 		// Column D
 		d := ((c / (math.Log(float64(fd.samples)) / math.Log(2.5))) / 2)
@@ -297,35 +423,35 @@ func (fd *Data) Next() {
 	sv := float64(0)
 	if fd.seasonality {
 		divisor := float64(0)
-		rad1 := (math.Sin(float64(fd.i)*degToRad(float64(1)/(float64(fd.seasonalityWave1)/float64(360)))) / 2) + 0.5
+		rad1 := (math.Sin(float64(i)*degToRad(float64(1)/(float64(fd.seasonalityWave1)/float64(360)))) / 2) + 0.5
 		if fd.seasonalityWave1 == 1 {
 			rad1 = 0
 		} else {
 			divisor++
 		}
 
-		rad2 := (math.Sin(float64(fd.i)*degToRad(float64(1)/(float64(fd.seasonalityWave2)/float64(360)))) / 2) + 0.5
+		rad2 := (math.Sin(float64(i)*degToRad(float64(1)/(float64(fd.seasonalityWave2)/float64(360)))) / 2) + 0.5
 		if fd.seasonalityWave2 == 1 {
 			rad2 = 0
 		} else {
 			divisor++
 		}
 
-		rad3 := (math.Sin(float64(fd.i)*degToRad(float64(1)/(float64(fd.seasonalityWave3)/float64(360)))) / 2) + 0.5
+		rad3 := (math.Sin(float64(i)*degToRad(float64(1)/(float64(fd.seasonalityWave3)/float64(360)))) / 2) + 0.5
 		if fd.seasonalityWave3 == 1 {
 			rad3 = 0
 		} else {
 			divisor++
 		}
 
-		rad4 := (math.Sin(float64(fd.i)*degToRad(float64(1)/(float64(fd.seasonalityWave4)/float64(360)))) / 2) + 0.5
+		rad4 := (math.Sin(float64(i)*degToRad(float64(1)/(float64(fd.seasonalityWave4)/float64(360)))) / 2) + 0.5
 		if fd.seasonalityWave4 == 1 {
 			rad4 = 0
 		} else {
 			divisor++
 		}
 
-		rad5 := (math.Sin(float64(fd.i)*degToRad(float64(1)/(float64(fd.seasonalityWave5)/float64(360)))) / 2) + 0.5
+		rad5 := (math.Sin(float64(i)*degToRad(float64(1)/(float64(fd.seasonalityWave5)/float64(360)))) / 2) + 0.5
 		if fd.seasonalityWave5 == 1 {
 			rad5 = 0
 		} else {
@@ -341,102 +467,338 @@ func (fd *Data) Next() {
 
 	// Let's do the permanent bump which includes a smoother as we're wither
 	// going up or down from a baseline
-	if fd.i >= fd.permaBumpAt && fd.permaBumpAt > 0 && fd.permaBumpSmoother > 0 {
+	if i >= fd.permaBumpAt && fd.permaBumpAt > 0 && fd.permaBumpSmoother > 0 {
 		bumpBaseline := (float64(fd.permaBumpBy) / 100) * fd.to
-		if fd.i-fd.permaBumpAt > fd.permaBumpSmoother {
+		if i-fd.permaBumpAt > fd.permaBumpSmoother {
 			f = f + bumpBaseline
 		} else {
-			tmp := (float64(fd.i-fd.permaBumpAt) / float64(fd.permaBumpSmoother))
+			tmp := (float64(i-fd.permaBumpAt) / float64(fd.permaBumpSmoother))
 			adjusted := bumpBaseline * tmp * tmp
 			f = f + adjusted
 		}
 	}
 
-	f = f + (float64(fd.i) * fd.slope) + fd.bump
-	v := f
+	return f + (float64(i) * fd.slope) + fd.bump
+}
 
-	// Let's stretch or squish. We need to do it both up and down.
-	stv := fd.stretchStart + (float64(fd.i) * fd.stretchStep)
-	if stv > 1 { // Stretch above 1 means "stretch"
-		if f > fd.f { // Stretching on the way up
-			v = f + ((f - fd.f) * stv)
-		} else if f < fd.f { // Stretching on the way down
-			v = f - ((fd.f - f) * stv)
-		} else { // Flat value so just use whatever the previous value was
-			v = fd.v
-		}
-	} else if stv < 1 { // Stretch below 1 means "squish"
-		v = f * stv
+// Val returns the current fake numeric value.
+func (fd *Data) Val() interface{} {
+	return fd.v
+}
+
+// Vals returns the next count of fake values as an interface{} array.
+func (fd *Data) Vals(count int) []interface{} {
+	return makeValues(fd, count)
+}
+
+// JSONStats retrieves the current stats as s JSON string.
+func (fd *Data) JSONStats() string {
+	return fd.Stats.JSON()
+}
+
+// ID returns the unique id.
+func (fd *Data) ID() string {
+	return fd.id
+}
+
+// Config returns the parameters of the Data as a DataConfig. A seed that was
+// taken from the current time is returned as the seed actually used, so the
+// config always recreates the same values.
+func (fd *Data) Config() DataConfig {
+	return DataConfig{
+		ID:      fd.id,
+		Samples: fd.samples,
+
+		StretchStart: fd.stretchStart,
+		StretchEnd:   fd.stretchEnd,
+		Slope:        fd.slope,
+		Bump:         fd.bump,
+		From:         fd.from,
+		To:           fd.to,
+		LimitUpper:   fd.limitUpper,
+		LimitLower:   fd.limitLower,
+
+		PermaBumpAt:       fd.permaBumpAt,
+		PermaBumpBy:       fd.permaBumpBy,
+		PermaBumpSmoother: fd.permaBumpSmoother,
+
+		UseRandom:     fd.useRandom,
+		Seed:          fd.rnd.state().Seed,
+		Bias:          fd.bias,
+		CounterRandom: fd.counterRandom,
+
+		Spike:             fd.spike,
+		SpikeEvery:        fd.spikeEvery,
+		SpikeSustain:      fd.spikeSustain,
+		SpikeTo:           fd.spikeTo,
+		SpikeWobble:       fd.spikeWobble,
+		SpikeWobbleFactor: fd.spikeWobbleFactor,
+		SpikeSmoother:     fd.spikeFirstSmoother,
+
+		Seasonality:      fd.seasonality,
+		SeasonalityWave1: fd.seasonalityWave1,
+		SeasonalityWave2: fd.seasonalityWave2,
+		SeasonalityWave3: fd.seasonalityWave3,
+		SeasonalityWave4: fd.seasonalityWave4,
+		SeasonalityWave5: fd.seasonalityWave5,
+
+		KeepStats: fd.keepStats,
 	}
+}
 
-	// Let's do spikes!
-	if fd.spike && fd.i >= fd.spikeStart && !(fd.i > fd.spikeEnd) {
-		multiplier := int64(0)
-		spikeValue := (float64(fd.spikeTo) / 100) * fd.to
+// Index returns the position of the current value, the first sample being 0.
+func (fd *Data) Index() int64 {
+	return fd.i - 1
+}
+
+// Spiking returns whether the current value is part of a spike, including
+// the samples smoothing the way up and down.
+func (fd *Data) Spiking() bool {
+	i := fd.Index()
+	return fd.spike && i >= fd.spikeStart && i <= fd.spikeEnd
+}
+
+// seekWindow is how many steps of the random walk SeekTo() remembers while
+// drawing the random numbers before the sample it jumps to.
+const seekWindow = 64
+
+// checkpointEvery is how many samples apart SeekTo() remembers the random
+// walk, so that seeking to a sample it walked past before only walks from the
+// closest checkpoint.
+const checkpointEvery = 4096
+
+// walkCheckpoints are the steps of the random walk of a Data right before
+// every checkpointEvery-th sample. They are shared with the copies At() makes
+// so that At() calls continue where the previous ones stopped.
+type walkCheckpoints struct {
+	mu   sync.Mutex
+	seed int64
+	b    []float64
+}
+
+// from returns the closest checkpoint at or before sample n and the step of
+// the random walk right before it.
+func (wc *walkCheckpoints) from(seed int64, n int64) (int64, float64) {
+	wc.mu.Lock()
+	defer wc.mu.Unlock()
+
+	if wc.seed != seed {
+		wc.seed = seed
+		wc.b = nil
+	}
+
+	j := n / checkpointEvery
+	if j > int64(len(wc.b)) {
+		j = int64(len(wc.b))
+	}
+
+	if j <= 0 {
+		return 0, 0
+	}
+
+	return j * checkpointEvery, wc.b[j-1]
+}
+
+// add remembers the step b of the random walk right before sample k if it
+// is the next checkpoint.
+func (wc *walkCheckpoints) add(seed int64, k int64, b float64) {
+	wc.mu.Lock()
+	defer wc.mu.Unlock()
+
+	if wc.seed == seed && k/checkpointEvery == int64(len(wc.b))+1 {
+		wc.b = append(wc.b, b)
+	}
+}
 
-		// Quick div/0 safety check
-		if fd.spikeWobbleFactor == 0 {
-			fd.spikeWobbleFactor = 1
+// SeekTo jumps to sample n (the first sample being 0). Slope, seasonality,
+// spikes and the permanent bump are calculated directly for sample n.
+//
+// The random walk of UseRandom is not a direct jump: every step depends on
+// the step before it, so the walk is taken up to sample n and the cost grows
+// with n. The walk is remembered every 4096 samples, so seeking (or calling
+// At()) again only walks from the closest sample seeked past before. A
+// backfill going forward therefore costs about the same per sample however
+// far it goes. Without CounterRandom the random numbers of every sample
+// before n are also drawn again from the seed. Only the sample jumped to is
+// added to the statistics.
+//
+// When stretching (a stretch above 1) a flat value repeats the previous
+// value, so a long run of flat values before sample n is generated in full.
+func (fd *Data) SeekTo(n int64) {
+	seed := fd.rnd.state().Seed
+	fd.rnd.restore(sourceState{Seed: seed})
+
+	// Walk up to sample n remembering the last few steps
+	var walk [seekWindow]float64
+	b := float64(0)
+	if fd.useRandom {
+		if fd.checkpoints == nil {
+			fd.checkpoints = &walkCheckpoints{}
 		}
 
-		// Quick div/0 safety check
-		if fd.spikeSmoother == 0 {
-			fd.spikeSmoother = 1
+		var from int64
+		from, b = fd.checkpoints.from(seed, n-seekWindow)
+		fd.rnd.restore(sourceState{Seed: seed, N: uint64(from)})
+
+		for k := from; k < n; k++ {
+			b = fd.walk(k, fd.rnd.Float64(), b)
+			walk[k%seekWindow] = b
+
+			if (k+1)%checkpointEvery == 0 {
+				fd.checkpoints.add(seed, k+1, b)
+			}
 		}
+	}
 
-		if fd.i >= fd.spikeStart && fd.i < (fd.spikeStart+fd.spikeSmoother) { // Going up?
-			multiplier = fd.spikeSmoother - ((fd.spikeStart + fd.spikeSmoother) - fd.i) + 1
-		} else if fd.i > (fd.spikeEnd - fd.spikeSmoother) { // Going down?
-			multiplier = fd.spikeEnd - fd.i + 1
+	walkAt := func(k int64) (float64, bool) {
+		if !fd.useRandom || k < 0 {
+			return 0, true
 		}
 
-		if multiplier == 0 {
-			if fd.spikeWobble {
-				if fd.spikeWobbleFactor > 0 {
-					v = spikeValue - ((a * spikeValue) / float64(fd.spikeWobbleFactor))
-				} else {
-					v = spikeValue + ((a * spikeValue) / float64(fd.spikeWobbleFactor))
-				}
-			} else {
-				v = spikeValue
-			}
-		} else {
-			// Let's apply a smoother as we're wither going up or down from the peak
-			tmp := (float64(1) - math.Abs(1/(float64(multiplier)))) * (1 + (1 / float64(fd.spikeSmoother)))
-			v = v + (tmp * tmp * (spikeValue - v))
+		return walk[k%seekWindow], n-k <= seekWindow
+	}
+
+	// Find the closest sample before n whose value does not depend on the
+	// value before it
+	k := n - 1
+	for ; k > 0; k-- {
+		if fd.stretchStart+(float64(k)*fd.stretchStep) <= 1 {
+			break
+		}
+
+		bk, ok := walkAt(k)
+		bPrev, okPrev := walkAt(k - 1)
+		if !ok || !okPrev {
+			k = 0
+			break
+		}
+
+		if fd.base(k, bk) != fd.base(k-1, bPrev) {
+			break
 		}
 	}
 
-	// Let's limit
-	if fd.limitLower && v < fd.from {
-		v = fd.from
-	} else if fd.limitUpper && v > fd.to {
-		v = fd.to
+	if k < 0 {
+		k = 0
 	}
 
-	// Setup next iteration
-	fd.f = f
-	fd.i = fd.i + 1
-	fd.v = v
+	// Generate from there on as Next() would
+	fd.seekSchedule(k)
+	fd.i = k
+	fd.b, _ = walkAt(k - 1)
+	fd.f = 0
+	fd.v = 0
+	if k > 0 {
+		fd.f = fd.base(k-1, fd.b)
+	}
 
-	if fd.keepStats {
-		fd.Stats.Add(fd.v)
+	fd.rnd.restore(sourceState{Seed: seed, N: uint64(k)})
+	for fd.i < n {
+		fd.step()
 	}
+
+	fd.Next()
 }
 
-// Val returns the current fake numeric value.
-func (fd *Data) Val() interface{} {
-	return fd.v
+// seekSchedule sets the spike schedule to what it is right before sample n is
+// generated, replaying only the points where Next() recalculates it.
+func (fd *Data) seekSchedule(n int64) {
+	fd.spikeCount = 0
+	fd.spikeStart = 0
+	fd.spikeEnd = 0
+
+	if n == 0 {
+		return
+	}
+
+	// Next() calculates the schedule at sample 0 and at the end of every spike
+	for fd.i = 0; ; fd.i = fd.spikeEnd {
+		fd.spikeCount++
+		fd.calculateNextSpikeStartStop()
+
+		if fd.spikeEnd <= fd.i || fd.spikeEnd >= n {
+			break
+		}
+	}
 }
 
-// Vals returns the next count of fake values as an interface{} array.
-func (fd *Data) Vals(count int) []interface{} {
-	return makeValues(fd, count)
+// At returns the value of sample n (the first sample being 0) without
+// changing the current value. Like SeekTo() it takes the random walk of
+// UseRandom up to sample n, from the closest sample seeked past before.
+func (fd *Data) At(n int64) float64 {
+	c := *fd
+	c.keepStats = false
+	c.rnd = generateRandom(fd.rnd.state().Seed, fd.counterRandom)
+	c.SeekTo(n)
+	return c.v
 }
 
-// JSONStats retrieves the current stats as s JSON string.
-func (fd *Data) JSONStats() string {
-	return fd.Stats.JSON()
+// dataState is the runtime state of a Data saved by Snapshot().
+type dataState struct {
+	ID          string      `json:"id"`
+	Source      sourceState `json:"source"`
+	SpikeCount  int64       `json:"spikeCount"`
+	SpikeStart  int64       `json:"spikeStart"`
+	SpikeEnd    int64       `json:"spikeEnd"`
+	B           float64     `json:"b"`
+	F           float64     `json:"f"`
+	I           int64       `json:"i"`
+	V           float64     `json:"v"`
+	Stats       DataStats   `json:"stats"`
+	Regression  regression  `json:"regression"`
+	CRegression regression  `json:"cumulativeRegression"`
+}
+
+// Snapshot saves the runtime state of the data, including the position of its
+// random numbers and its statistics, so it can be restored later with
+// Restore().
+func (fd *Data) Snapshot() ([]byte, error) {
+	// Slopes are only calculated by JSON() and may not be a number yet
+	stats := *fd.Stats
+	stats.CSlope = 0
+	stats.Slope = 0
+
+	return json.Marshal(dataState{
+		ID:          fd.id,
+		Source:      fd.rnd.state(),
+		SpikeCount:  fd.spikeCount,
+		SpikeStart:  fd.spikeStart,
+		SpikeEnd:    fd.spikeEnd,
+		B:           fd.b,
+		F:           fd.f,
+		I:           fd.i,
+		V:           fd.v,
+		Stats:       stats,
+		Regression:  *fd.Stats.regression,
+		CRegression: *fd.Stats.cRegression,
+	})
+}
+
+// Restore restores the runtime state saved by Snapshot(). The data must have
+// been created with the same parameters as the one that was saved and will
+// continue with the identical sequence.
+func (fd *Data) Restore(b []byte) error {
+	var st dataState
+	if err := json.Unmarshal(b, &st); err != nil {
+		return err
+	}
+
+	if st.ID != fd.id {
+		return errors.New("cannot restore a fake data with id '" + fd.id + "' from a snapshot of id '" + st.ID + "'")
+	}
+
+	fd.rnd.restore(st.Source)
+	fd.spikeCount = st.SpikeCount
+	fd.spikeStart = st.SpikeStart
+	fd.spikeEnd = st.SpikeEnd
+	fd.b = st.B
+	fd.f = st.F
+	fd.i = st.I
+	fd.v = st.V
+	*fd.Stats = st.Stats
+	fd.Stats.regression = &st.Regression
+	fd.Stats.cRegression = &st.CRegression
+	return nil
 }
 
 // Float returns the current value as a float64.
@@ -447,13 +809,274 @@ func (fd *Data) Float() float64 {
 // Floats returns the next count of fake values as a float64 array.
 func (fd *Data) Floats(count int) []float64 {
 	out := make([]float64, count)
+	fd.Fill(out)
+	return out
+}
+
+// Step returns the current value and generates the next one.
+func (fd *Data) Step() float64 {
+	out := fd.Float()
+	fd.Next()
+	return out
+}
 
-	for i := 0; i < count; i++ {
-		out[i] = fd.Float()
+// Fill writes the next len(dst) values into dst without allocating and
+// returns how many were written.
+func (fd *Data) Fill(dst []float64) int {
+	for i := range dst {
+		dst[i] = fd.Float()
 		fd.Next()
 	}
 
-	return out
+	return len(dst)
+}
+
+// DataConfig holds every parameter of a Data. It is the declarative
+// alternative to the long list of NewData arguments and can be validated
+// before a Data is created. See NewData for what each field does.
+type DataConfig struct {
+	ID      string `json:"id" yaml:"id"`
+	Samples int64  `json:"samples" yaml:"samples"`
+
+	StretchStart float64 `json:"stretchStart" yaml:"stretchStart"`
+	StretchEnd   float64 `json:"stretchEnd" yaml:"stretchEnd"`
+	Slope        float64 `json:"slope" yaml:"slope"`
+	Bump         float64 `json:"bump" yaml:"bump"`
+	From         float64 `json:"from" yaml:"from"`
+	To           float64 `json:"to" yaml:"to"`
+	LimitUpper   bool    `json:"limitUpper" yaml:"limitUpper"`
+	LimitLower   bool    `json:"limitLower" yaml:"limitLower"`
+
+	PermaBumpAt       int64   `json:"permaBumpAt" yaml:"permaBumpAt"`
+	PermaBumpBy       float64 `json:"permaBumpBy" yaml:"permaBumpBy"`
+	PermaBumpSmoother int64   `json:"permaBumpSmoother" yaml:"permaBumpSmoother"`
+
+	UseRandom bool    `json:"useRandom" yaml:"useRandom"`
+	Seed      int64   `json:"seed" yaml:"seed"`
+	Bias      float64 `json:"bias" yaml:"bias"`
+
+	// Use a counter-based random number generator. It generates different
+	// numbers than the default one for the same seed but lets SeekTo() and At()
+	// draw the random number of any sample directly.
+	CounterRandom bool `json:"counterRandom" yaml:"counterRandom"`
+
+	Spike             bool  `json:"spike" yaml:"spike"`
+	SpikeEvery        int64 `json:"spikeEvery" yaml:"spikeEvery"`
+	SpikeSustain      int64 `json:"spikeSustain" yaml:"spikeSustain"`
+	SpikeTo           int64 `json:"spikeTo" yaml:"spikeTo"`
+	SpikeWobble       bool  `json:"spikeWobble" yaml:"spikeWobble"`
+	SpikeWobbleFactor int64 `json:"spikeWobbleFactor" yaml:"spikeWobbleFactor"`
+	SpikeSmoother     int64 `json:"spikeSmoother" yaml:"spikeSmoother"`
+
+	Seasonality      bool  `json:"seasonality" yaml:"seasonality"`
+	SeasonalityWave1 int64 `json:"seasonalityWave1" yaml:"seasonalityWave1"`
+	SeasonalityWave2 int64 `json:"seasonalityWave2" yaml:"seasonalityWave2"`
+	SeasonalityWave3 int64 `json:"seasonalityWave3" yaml:"seasonalityWave3"`
+	SeasonalityWave4 int64 `json:"seasonalityWave4" yaml:"seasonalityWave4"`
+	SeasonalityWave5 int64 `json:"seasonalityWave5" yaml:"seasonalityWave5"`
+
+	KeepStats bool `json:"keepStats" yaml:"keepStats"`
+}
+
+// DataOption changes a DataConfig. Options are applied in order by
+// NewDataConfig.
+type DataOption func(*DataConfig)
+
+// WithStretch sets the stretch at the first and last sample.
+func WithStretch(start float64, end float64) DataOption {
+	return func(cfg *DataConfig) {
+		cfg.StretchStart = start
+		cfg.StretchEnd = end
+	}
+}
+
+// WithSlope sets the slope of the generated data.
+func WithSlope(slope float64) DataOption {
+	return func(cfg *DataConfig) {
+		cfg.Slope = slope
+	}
+}
+
+// WithBump bumps every value up (or down if negative).
+func WithBump(bump float64) DataOption {
+	return func(cfg *DataConfig) {
+		cfg.Bump = bump
+	}
+}
+
+// WithRange sets the "from" and "to" values of the generated data.
+func WithRange(from float64, to float64) DataOption {
+	return func(cfg *DataConfig) {
+		cfg.From = from
+		cfg.To = to
+	}
+}
+
+// WithLimits sets whether values are clamped to "from" and "to".
+func WithLimits(lower bool, upper bool) DataOption {
+	return func(cfg *DataConfig) {
+		cfg.LimitLower = lower
+		cfg.LimitUpper = upper
+	}
+}
+
+// WithPermaBump enables a permanent bump at sample "at" by a percentage of
+// "to", reached over "smoother" samples.
+func WithPermaBump(at int64, by float64, smoother int64) DataOption {
+	return func(cfg *DataConfig) {
+		cfg.PermaBumpAt = at
+		cfg.PermaBumpBy = by
+		cfg.PermaBumpSmoother = smoother
+	}
+}
+
+// WithRandom enables random numbers using the given seed and bias.
+func WithRandom(seed int64, bias float64) DataOption {
+	return func(cfg *DataConfig) {
+		cfg.UseRandom = true
+		cfg.Seed = seed
+		cfg.Bias = bias
+	}
+}
+
+// WithCounterRandom uses a counter-based random number generator.
+func WithCounterRandom() DataOption {
+	return func(cfg *DataConfig) {
+		cfg.CounterRandom = true
+	}
+}
+
+// WithSpike enables spikes every n samples to a percentage of "to", sustained
+// for a number of samples and reached over "smoother" samples.
+func WithSpike(every int64, sustain int64, to int64, smoother int64) DataOption {
+	return func(cfg *DataConfig) {
+		cfg.Spike = true
+		cfg.SpikeEvery = every
+		cfg.SpikeSustain = sustain
+		cfg.SpikeTo = to
+		cfg.SpikeSmoother = smoother
+	}
+}
+
+// WithSpikeWobble adds a little variance to sustained spikes.
+func WithSpikeWobble(factor int64) DataOption {
+	return func(cfg *DataConfig) {
+		cfg.SpikeWobble = true
+		cfg.SpikeWobbleFactor = factor
+	}
+}
+
+// WithSeasonality enables seasonality using up to 5 waves. Waves that are not
+// given are disabled (set to 1).
+func WithSeasonality(waves ...int64) DataOption {
+	return func(cfg *DataConfig) {
+		cfg.Seasonality = true
+		w := []*int64{&cfg.SeasonalityWave1, &cfg.SeasonalityWave2, &cfg.SeasonalityWave3, &cfg.SeasonalityWave4, &cfg.SeasonalityWave5}
+		for i := range w {
+			*w[i] = 1
+			if i < len(waves) {
+				*w[i] = waves[i]
+			}
+		}
+	}
+}
+
+// WithStats enables keeping internal statistics.
+func WithStats() DataOption {
+	return func(cfg *DataConfig) {
+		cfg.KeepStats = true
+	}
+}
+
+// NewDataConfig creates a DataConfig with sensible defaults and applies the
+// given options. By default data goes from 0 to 100 without stretching,
+// randomness, spikes or seasonality and a seed of 0 is used.
+func NewDataConfig(id string, samples int64, opts ...DataOption) DataConfig {
+	cfg := DataConfig{
+		ID:                id,
+		Samples:           samples,
+		StretchStart:      1,
+		StretchEnd:        1,
+		From:              0,
+		To:                100,
+		Bias:              0.5,
+		SpikeWobbleFactor: 1,
+		SeasonalityWave1:  1,
+		SeasonalityWave2:  1,
+		SeasonalityWave3:  1,
+		SeasonalityWave4:  1,
+		SeasonalityWave5:  1,
+	}
+
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	return cfg
+}
+
+// Validate checks the config for values that would generate nonsense or fail
+// at runtime. It returns nil or a *ConfigError listing every invalid field.
+// Spike, random and seasonality fields are only checked when enabled.
+func (cfg DataConfig) Validate() error {
+	ce := &ConfigError{Kind: "data", ID: cfg.ID}
+
+	if cfg.ID == "" {
+		ce.add("ID", cfg.ID, "cannot be blank")
+	}
+
+	if cfg.Samples <= 0 {
+		ce.add("Samples", cfg.Samples, "must be greater than 0")
+	}
+
+	if cfg.From > cfg.To {
+		ce.add("From", cfg.From, "cannot be greater than To ("+fmt.Sprintf("%v", cfg.To)+")")
+	}
+
+	if cfg.StretchStart < 0 {
+		ce.add("StretchStart", cfg.StretchStart, "cannot be less than 0")
+	}
+
+	if cfg.StretchEnd < 0 {
+		ce.add("StretchEnd", cfg.StretchEnd, "cannot be less than 0")
+	}
+
+	if cfg.PermaBumpAt < 0 {
+		ce.add("PermaBumpAt", cfg.PermaBumpAt, "cannot be less than 0")
+	}
+
+	if cfg.PermaBumpSmoother < 0 {
+		ce.add("PermaBumpSmoother", cfg.PermaBumpSmoother, "cannot be less than 0")
+	}
+
+	if cfg.UseRandom && (cfg.Bias < 0 || cfg.Bias > 1) {
+		ce.add("Bias", cfg.Bias, "must be between 0 and 1")
+	}
+
+	if cfg.Spike {
+		if cfg.SpikeSmoother < 0 {
+			ce.add("SpikeSmoother", cfg.SpikeSmoother, "cannot be less than 0")
+		}
+
+		if cfg.SpikeEvery <= cfg.SpikeSmoother {
+			ce.add("SpikeEvery", cfg.SpikeEvery, "must be greater than SpikeSmoother ("+fmt.Sprintf("%v", cfg.SpikeSmoother)+")")
+		}
+
+		if cfg.SpikeSustain < 0 {
+			ce.add("SpikeSustain", cfg.SpikeSustain, "cannot be less than 0")
+		}
+	}
+
+	if cfg.Seasonality {
+		waves := []int64{cfg.SeasonalityWave1, cfg.SeasonalityWave2, cfg.SeasonalityWave3, cfg.SeasonalityWave4, cfg.SeasonalityWave5}
+		for i, w := range waves {
+			if w < 1 {
+				ce.add("SeasonalityWave"+fmt.Sprintf("%v", i+1), w, "must be at least 1")
+			}
+		}
+	}
+
+	return ce.err()
 }
 
 // NewData creates a new fake data. Data has a unique id, number of required
@@ -623,58 +1246,117 @@ func NewData(
 
 	keepStats bool) (*Data, error) {
 
-	stretchStep := math.Abs(math.Abs(stretchEnd)-math.Abs(stretchStart)) / float64(samples)
-	if stretchEnd < stretchStart {
+	return NewDataFromConfig(DataConfig{
+		ID:      id,
+		Samples: samples,
+
+		StretchStart: stretchStart,
+		StretchEnd:   stretchEnd,
+		Slope:        slope,
+		Bump:         bump,
+		From:         from,
+		To:           to,
+		LimitUpper:   limitUpper,
+		LimitLower:   limitLower,
+
+		PermaBumpAt:       permaBumpAt,
+		PermaBumpBy:       permaBumpBy,
+		PermaBumpSmoother: permaBumpSmoother,
+
+		UseRandom: useRandom,
+		Seed:      seed,
+		Bias:      bias,
+
+		Spike:             spike,
+		SpikeEvery:        spikeEvery,
+		SpikeSustain:      spikeSustain,
+		SpikeTo:           spikeTo,
+		SpikeWobble:       spikeWobble,
+		SpikeWobbleFactor: spikeWobbleFactor,
+		SpikeSmoother:     spikeSmoother,
+
+		Seasonality:      seasonality,
+		SeasonalityWave1: seasonalityWave1,
+		SeasonalityWave2: seasonalityWave2,
+		SeasonalityWave3: seasonalityWave3,
+		SeasonalityWave4: seasonalityWave4,
+		SeasonalityWave5: seasonalityWave5,
+
+		KeepStats: keepStats,
+	})
+}
+
+// NewDataFromConfig creates a new fake data from a DataConfig. The config is
+// validated first and a *ConfigError listing every invalid field is returned
+// if it is not valid. See NewData for what each parameter does.
+func NewDataFromConfig(cfg DataConfig) (*Data, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	stretchStep := math.Abs(math.Abs(cfg.StretchEnd)-math.Abs(cfg.StretchStart)) / float64(cfg.Samples)
+	if cfg.StretchEnd < cfg.StretchStart {
 		stretchStep = stretchStep * -1
 	}
 
+	// Quick div/0 safety check
+	if cfg.SpikeWobbleFactor == 0 {
+		cfg.SpikeWobbleFactor = 1
+	}
+
+	// Quick div/0 safety check, the first spike is still scheduled with the
+	// configured smoother
+	spikeSmoother := cfg.SpikeSmoother
+	if spikeSmoother == 0 {
+		spikeSmoother = 1
+	}
+
 	d := &Data{
-		id:      id,
-		samples: samples,
+		id:      cfg.ID,
+		samples: cfg.Samples,
 
-		stretchStart: stretchStart,
-		stretchEnd:   stretchEnd,
+		stretchStart: cfg.StretchStart,
+		stretchEnd:   cfg.StretchEnd,
 		stretchStep:  stretchStep,
-		slope:        slope,
-		bump:         bump,
-		from:         from,
-		to:           to,
-		limitUpper:   limitUpper,
-		limitLower:   limitLower,
-
-		useRandom: useRandom,
-		rnd:       generateRandom(seed),
-		bias:      bias,
-
-		permaBumpAt:       permaBumpAt,
-		permaBumpBy:       permaBumpBy,
-		permaBumpSmoother: permaBumpSmoother,
-
-		spike:             spike,
-		spikeSustain:      spikeSustain,
-		spikeEvery:        spikeEvery,
-		spikeTo:           spikeTo,
-		spikeWobble:       spikeWobble,
-		spikeWobbleFactor: spikeWobbleFactor,
+		slope:        cfg.Slope,
+		bump:         cfg.Bump,
+		from:         cfg.From,
+		to:           cfg.To,
+		limitUpper:   cfg.LimitUpper,
+		limitLower:   cfg.LimitLower,
+
+		useRandom:     cfg.UseRandom,
+		counterRandom: cfg.CounterRandom,
+		rnd:           generateRandom(cfg.Seed, cfg.CounterRandom),
+		bias:          cfg.Bias,
+
+		permaBumpAt:       cfg.PermaBumpAt,
+		permaBumpBy:       cfg.PermaBumpBy,
+		permaBumpSmoother: cfg.PermaBumpSmoother,
+
+		spike:             cfg.Spike,
+		spikeSustain:      cfg.SpikeSustain,
+		spikeEvery:        cfg.SpikeEvery,
+		spikeTo:           cfg.SpikeTo,
+		spikeWobble:       cfg.SpikeWobble,
+		spikeWobbleFactor: cfg.SpikeWobbleFactor,
 		spikeSmoother:     spikeSmoother,
 
-		seasonality:      seasonality,
-		seasonalityWave1: seasonalityWave1,
-		seasonalityWave2: seasonalityWave2,
-		seasonalityWave3: seasonalityWave3,
-		seasonalityWave4: seasonalityWave4,
-		seasonalityWave5: seasonalityWave5,
-
-		keepStats: keepStats,
-		Stats: &DataStats{
-			ID:          id,
-			From:        from,
-			To:          to,
-			Seed:        seed,
-			regression:  linear.NewRegression(),
-			cRegression: linear.NewRegression(),
-		},
+		spikeFirstSmoother: cfg.SpikeSmoother,
+
+		seasonality:      cfg.Seasonality,
+		seasonalityWave1: cfg.SeasonalityWave1,
+		seasonalityWave2: cfg.SeasonalityWave2,
+		seasonalityWave3: cfg.SeasonalityWave3,
+		seasonalityWave4: cfg.SeasonalityWave4,
+		seasonalityWave5: cfg.SeasonalityWave5,
+
+		keepStats: cfg.KeepStats,
+		Stats:     NewDataStats(cfg.ID, cfg.From, cfg.To),
+
+		checkpoints: &walkCheckpoints{},
 	}
+	d.Stats.Seed = cfg.Seed
 
 	d.Next()
 	return d, nil
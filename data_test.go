@@ -91,3 +91,124 @@ func ExampleNewData_b() {
 	}
 	// Output: -57.280531906981736 -57.280531906981736 -57.280531906981736 -57.280531906981736 -57.280531906981736 -57.280531906981736 -57.280531906981736 -57.280531906981736 -57.280531906981736 -57.280531906981736
 }
+
+func ExampleNewDataFromConfig() {
+	cfg := NewDataConfig("d1", 10, WithRange(50, 100), WithRandom(1, 0.5))
+	fd, _ := NewDataFromConfig(cfg)
+	fmt.Printf("%v\n", fd.Vals(10))
+	// Output: [-57.280531906981736 -63.071999277046245 -63.88021576640003 -63.764429475766235 -63.59492175044973 -64.63661509786012 -59.00562870317643 -55.48448571431909 -50.63657453451286 -49.45361566654604]
+}
+
+func ExampleDataConfig_Validate() {
+	cfg := NewDataConfig("d1", 0, WithRange(100, 50), WithSpike(10, 0, 100, 20))
+	err := cfg.Validate()
+	fmt.Println(err)
+
+	if ce, ok := err.(*ConfigError); ok {
+		fmt.Println(ce.Field("SpikeEvery").Value)
+	}
+	// Output:
+	// invalid fake data with id 'd1': Samples must be greater than 0 but was '0'; From cannot be greater than To (50) but was '100'; SpikeEvery must be greater than SpikeSmoother (20) but was '10'
+	// 10
+}
+
+func ExampleData_Snapshot() {
+	cfg := NewDataConfig("d1", 1000, WithRandom(1, 0.5), WithSpike(100, 10, 150, 5), WithSpikeWobble(3), WithSeasonality(50, 200), WithStats())
+	fd1, _ := NewDataFromConfig(cfg)
+	fd1.Floats(400)
+
+	snapshot, _ := fd1.Snapshot()
+	want := fd1.Floats(600)
+
+	fd2, _ := NewDataFromConfig(cfg)
+	fd2.Restore(snapshot)
+	got := fd2.Floats(600)
+
+	same := fd1.JSONStats() == fd2.JSONStats()
+	for i := range want {
+		if want[i] != got[i] {
+			same = false
+		}
+	}
+	fmt.Println(same)
+	// Output: true
+}
+
+func ExampleData_SeekTo() {
+	cfg := NewDataConfig("d1", 10, WithRange(50, 100), WithRandom(1, 0.5))
+	fd, _ := NewDataFromConfig(cfg)
+
+	fmt.Println(fd.At(7))
+	fd.SeekTo(5)
+	fmt.Printf("%v\n", fd.Vals(5))
+	// Output:
+	// -55.48448571431909
+	// [-64.63661509786012 -59.00562870317643 -55.48448571431909 -50.63657453451286 -49.45361566654604]
+}
+
+func ExampleData_Spiking() {
+	fd, _ := NewDataFromConfig(NewDataConfig("d1", 20, WithSpike(8, 1, 90, 2)))
+
+	var spiking []int64
+	for i := 0; i < 20; i++ {
+		if fd.Spiking() {
+			spiking = append(spiking, fd.Index())
+		}
+		fd.Next()
+	}
+
+	fmt.Println(spiking, fd.Config().SpikeEvery)
+	// Output: [6 7 8 9 10 12 13 14 15 16 18 19] 8
+}
+
+// A SpikeSmoother of 0 schedules the first spike as NewData always has, the
+// ones after it as if it were 1.
+func ExampleData_Spiking_noSmoother() {
+	cfg := NewDataConfig("d1", 40, WithRange(0, 100), WithSpike(10, 2, 90, 0))
+	fd, _ := NewDataFromConfig(cfg)
+
+	var spiking, peaks []int64
+	for i := 0; i < 40; i++ {
+		if fd.Spiking() {
+			spiking = append(spiking, fd.Index())
+		}
+		if fd.Float() == 90 {
+			peaks = append(peaks, fd.Index())
+		}
+		fd.Next()
+	}
+
+	fmt.Println(spiking)
+	fmt.Println(peaks, fd.At(11), fd.At(20), fd.Config().SpikeSmoother)
+	// Output:
+	// [10 11 18 19 20 21 27 28 29 30 36 37 38 39]
+	// [11 19 20 21 28 29 30 37 38 39] 90 90 0
+}
+
+// A backfill with At() only walks from where the previous call stopped.
+func ExampleData_At_backfill() {
+	for _, counter := range []bool{true, false} {
+		opts := []DataOption{WithRandom(3, 0.5)}
+		if counter {
+			opts = append(opts, WithCounterRandom())
+		}
+
+		fd, _ := NewDataFromConfig(NewDataConfig("d1", 20000, opts...))
+		want := fd.Floats(20000)
+
+		seek, _ := NewDataFromConfig(NewDataConfig("d1", 20000, opts...))
+		same := true
+		for _, n := range []int64{12000, 3, 4095, 4096, 4160, 4161, 8191, 19999, 12000} {
+			seek.SeekTo(n)
+			same = same && seek.Float() == want[n] && fd.At(n) == want[n]
+		}
+
+		for n := int64(0); n < 20000; n += 97 {
+			same = same && fd.At(n) == want[n]
+		}
+		fmt.Println(counter, same)
+	}
+	// Output:
+	// true true
+	// false true
+}
@@ -43,6 +43,12 @@
 // and then call fakeType.Next() to generate a next value once and
 // fakeType.Value() to retrieve it one or more times.
 //
+// Data has a lot of parameters so it can also be created from a DataConfig,
+// which is validated before any values are generated:
+//
+//  cfg := fake.NewDataConfig("cpu", 1000, fake.WithRange(0, 100), fake.WithRandom(1, 0.5))
+//  fakeData, err := fake.NewDataFromConfig(cfg)
+//
 // The goal is to generate time series data that may look as follows:
 //
 //  Timestamp, CPU, Memory
@@ -90,6 +96,134 @@
 //      }
 //  }
 //
+// More irregular patterns are written as a sequence of good (G) and bad (B)
+// samples with repeated groups, optionally shifted by a phase. Patterns repeat
+// exactly, older versions drifted for some cycles (e.g. 1 good and 4 bad
+// samples was bad from sample 11 on) so those generate different values now.
+// Bad samples at fixed times of day, e.g. a nightly backup, come from a time
+// pattern:
+//
+//  fakePattern, err := fake.NewPatternFromConfig(fake.PatternConfig{ID: "p", Sequence: "(G*23 B)*6 G*20 B*4", Phase: 3})
+//  fakeBackup, err := fake.NewTimePattern("backup", fakeTime, []string{"02:00-02:15"}, true)
+//
+// A Random is bad at random samples so bad samples rarely follow each other.
+// Real outages come in bursts, use a BurstRandom for those. It is bad just as
+// often in the long run but in runs of a given mean length:
+//
+//  fakeRandom, err := fake.NewBurstRandom("network", 1, 0.95, 10, true)
+//
+// Instead of combining gates by hand a Combinator combines them into another
+// gate. And, Or, Xor, Not, AtLeast and Majority advance their gates together
+// and, WithStats, count which of them caused every bad sample:
+//
+//  outage := fake.And(fakePattern, fake.Or(fakeRandom, fakeBackup)).Named("outage").WithStats()
+//
+// Counters and states
+//
+// Data generates gauges. A Counter turns a Data into a monotonically
+// increasing counter (e.g. requests or bytes received) that goes up by the
+// value of the Data every sample. Like real counters it can be reset, by a
+// Gate or at given samples, and wrap around:
+//
+//  rate, err := fake.NewDataFromConfig(fake.NewDataConfig("rate", 1000, fake.WithRange(0, 20)))
+//  fakeCounter, err := fake.NewCounter("requests", rate, true, fake.WithResets(fakeRandom), fake.WithWrap(32))
+//
+// Status fields such as "UP", "DEGRADED" and "DOWN" come from a Categorical
+// that draws weighted states or walks a Markov chain of transitions:
+//
+//  fakeStatus, err := fake.NewCategorical("status", 1, []string{"200", "404", "500"}, []float64{90, 7, 3}, true)
+//
+// Scenarios
+//
+// Instead of wiring every type up by hand a whole simulation can be described
+// in a JSON or YAML file (see ScenarioSpec) and loaded with LoadScenarioFile.
+// A Scenario advances its Time, sample gates and Data series in lock-step and
+// returns a Row per sample:
+//
+//  scenario, err := fake.LoadScenarioFile("scenario.yaml")
+//
+//  for _, row := range scenario.Rows(1000) {
+//      if row.Good {
+//          fmt.Printf("%v: %v\n", row.Time, row.Fields)
+//      }
+//  }
+//
+// Random access
+//
+// SeekTo and At jump to any sample of a Pattern, Random, Time or Data without
+// generating the ones before it, e.g. to backfill a gap:
+//
+//  v := fakeData.At(1000000)
+//
+// Slope, seasonality, spikes and the permanent bump are calculated directly
+// but the random walk of UseRandom is not: every step depends on the one
+// before, so the first jump to sample n walks n steps. The walk is remembered
+// every 4096 samples and At continues where the previous call stopped, so a
+// backfill costs about the same per sample however far it goes. For every
+// type, without CounterRandom the random numbers before n are drawn again
+// from the seed, which is cheap but also grows with n.
+//
+// Concurrency
+//
+// The types in this package do not guard their state, so a single generator
+// may only be used by one goroutine at a time. Generators never share state
+// with each other though, so different generators can run in parallel freely.
+// Use Synchronized to share one generator between goroutines and an Engine to
+// generate thousands of independent series on a pool of workers, merged in
+// timestamp order:
+//
+//  engine := &fake.Engine{Series: series}
+//
+//  for row := range engine.Run(ctx, 1000) {
+//      fmt.Printf("%v %v: %v\n", row.Time, row.Series, row.Value)
+//  }
+//
+// Real time
+//
+// A Pacer emits rows when the clock reaches their generated timestamp, e.g.
+// to feed a dashboard live. Speed it up to replay an hour every minute and
+// choose whether late rows are emitted in a burst, skipped or shift the
+// schedule:
+//
+//  pacer := &fake.Pacer{Time: ft, Values: []fake.Value{fd}, Speed: 60}
+//
+//  pacer.Run(ctx, func(row fake.PacedRow) error {
+//      fmt.Printf("%v: %v\n", row.Time, row.Values[0])
+//      return nil
+//  })
+//
+// Output
+//
+// Writing generated rows out is left to subpackages, one per format:
+//
+//  csvout      CSV with configurable timestamps, precision and bad samples
+//  influxout   InfluxDB line protocol with tags and batched writes
+//  promout     a Prometheus scrape target with simulated scrape failures,
+//              remote write and OpenMetrics
+//  graphiteout Graphite plaintext
+//  opentsdbout OpenTSDB "put" lines
+//  statsdout   StatsD gauges, counters and timers with sample rates
+//  jsonlout    JSON Lines in any shape from a template or field mapping
+//
+// To see what a series looks like while tuning its parameters, termchart
+// draws it in the terminal (also "fakets preview -chart"). For reviews and
+// golden tests chart renders the same as deterministic SVG or PNG images.
+// The playground package serves a web page with a slider for every parameter
+// of NewData that exports the result as a DataConfig.
+//
+// Command line
+//
+// The fakets command (in cmd/fakets) generates data without writing any Go.
+// Its flags map onto the parameters of NewTime, NewData, NewPattern and
+// NewRandom, or it reads a scenario file:
+//
+//  go install github.com/powerpu/go-fake-ts/cmd/fakets@latest
+//
+//  fakets generate -samples 1440 -from 0 -to 100 -useRandom -seed 1 -pattern 23:1
+//  fakets preview -spec scenario.yaml -rows 20
+//  fakets validate -spec scenario.yaml
+//  fakets playground -useRandom -seed 1
+//
 // You can go as wild or as simple as you like. Remember, you're only limited by
 // your imagination!
 package fake
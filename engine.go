@@ -0,0 +1,244 @@
+package fake
+
+import (
+	"container/heap"
+	"context"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// EngineSeries is a single independent series run by an Engine. Every series
+// has its own Time so series can be generated in parallel.
+type EngineSeries struct {
+	// The ID of the series, copied to every row.
+	ID string
+
+	// The time of every sample. Timestamps of a series should not go
+	// backwards (i.e. the variance should be less than the increment) or rows
+	// will not be merged in timestamp order.
+	Time *Time
+
+	// The value of every sample.
+	Data *Data
+
+	// Decides whether a sample is "good". Optional.
+	Gate Gate
+
+	// Runtime variables, only ever touched by the worker generating a batch
+	i int64
+}
+
+// EngineRow is a single sample of an EngineSeries.
+type EngineRow struct {
+	// The ID of the series.
+	Series string `json:"series"`
+
+	// Position of the sample within its series, starting at 0.
+	Index int64 `json:"index"`
+
+	// The time of the sample.
+	Time time.Time `json:"time"`
+
+	// The value of the sample.
+	Value float64 `json:"value"`
+
+	// Whether the gate of the series is "good" (always true without a gate).
+	Good bool `json:"good"`
+}
+
+// Engine generates many independent series on a pool of workers and merges
+// their rows in timestamp order. Rows with the same timestamp are returned in
+// the order of Series.
+type Engine struct {
+	// The series to generate. Each series is only ever used by one worker at
+	// a time and must not be used elsewhere while the engine is running.
+	Series []*EngineSeries
+
+	// Number of workers. Defaults to the number of CPUs.
+	Workers int
+
+	// Number of samples generated per series at a time. Defaults to 1024.
+	Batch int
+}
+
+// Run generates the given number of samples for every series and returns the
+// merged rows on a channel. The channel is closed when all rows have been
+// sent or the context is done.
+//
+// Running the engine again continues every series where the previous run
+// stopped, so samples is the number of samples of this run and not a total.
+// Rows generated ahead but not sent before the context was done are skipped.
+func (e *Engine) Run(ctx context.Context, samples int64) <-chan EngineRow {
+	workers := e.Workers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+
+	batch := e.Batch
+	if batch <= 0 {
+		batch = 1024
+	}
+
+	out := make(chan EngineRow, batch)
+
+	go func() {
+		defer close(out)
+
+		// Every series has at most one batch requested at a time so neither
+		// the requests nor the results ever block.
+		jobs := make(chan int, len(e.Series))
+		results := make([]chan []EngineRow, len(e.Series))
+		for i := range results {
+			results[i] = make(chan []EngineRow, 1)
+		}
+
+		var wg sync.WaitGroup
+		defer wg.Wait()
+		defer close(jobs)
+
+		// Where every series stops, no worker of a previous run is left
+		ends := make([]int64, len(e.Series))
+		for i, es := range e.Series {
+			ends[i] = es.i + samples
+		}
+
+		for w := 0; w < workers; w++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for i := range jobs {
+					results[i] <- e.Series[i].generate(batch, ends[i])
+				}
+			}()
+		}
+
+		// Samples of every series not requested yet and whether a batch has
+		// been requested but not received
+		left := make([]int64, len(e.Series))
+		requested := make([]bool, len(e.Series))
+
+		request := func(i int) {
+			if left[i] <= 0 {
+				return
+			}
+
+			left[i] -= int64(batch)
+			requested[i] = true
+			jobs <- i
+		}
+
+		// Rows of the current batch of every series still to be sent
+		pending := make([][]EngineRow, len(e.Series))
+		h := &rowHeap{}
+
+		receive := func(i int) bool {
+			select {
+			case pending[i] = <-results[i]:
+			case <-ctx.Done():
+				return false
+			}
+
+			// Generate the next batch while this one is being sent
+			requested[i] = false
+			request(i)
+			if len(pending[i]) > 0 {
+				heap.Push(h, rowHead{t: pending[i][0].Time, series: i})
+			}
+			return true
+		}
+
+		for i := range e.Series {
+			left[i] = samples
+			request(i)
+		}
+
+		for i := range e.Series {
+			if requested[i] && !receive(i) {
+				return
+			}
+		}
+
+		for h.Len() > 0 {
+			i := h.heads[0].series
+
+			select {
+			case out <- pending[i][0]:
+			case <-ctx.Done():
+				return
+			}
+
+			pending[i] = pending[i][1:]
+			if len(pending[i]) > 0 {
+				h.heads[0].t = pending[i][0].Time
+				heap.Fix(h, 0)
+				continue
+			}
+
+			heap.Pop(h)
+			if requested[i] && !receive(i) {
+				return
+			}
+		}
+	}()
+
+	return out
+}
+
+// generate generates the next batch of rows, stopping before the sample with
+// index end.
+func (es *EngineSeries) generate(batch int, end int64) []EngineRow {
+	n := end - es.i
+	if n > int64(batch) {
+		n = int64(batch)
+	}
+
+	if n <= 0 {
+		return nil
+	}
+
+	rows := make([]EngineRow, n)
+	for j := range rows {
+		good := true
+		if es.Gate != nil {
+			good = es.Gate.Good()
+			es.Gate.Next()
+		}
+
+		rows[j] = EngineRow{Series: es.ID, Index: es.i, Time: es.Time.Step(), Value: es.Data.Step(), Good: good}
+		es.i++
+	}
+
+	return rows
+}
+
+// rowHead is the next row of a series waiting to be merged.
+type rowHead struct {
+	t      time.Time
+	series int
+}
+
+// rowHeap orders the next rows of all series by timestamp and series.
+type rowHeap struct {
+	heads []rowHead
+}
+
+func (h *rowHeap) Len() int { return len(h.heads) }
+
+func (h *rowHeap) Less(i, j int) bool {
+	if h.heads[i].t.Equal(h.heads[j].t) {
+		return h.heads[i].series < h.heads[j].series
+	}
+
+	return h.heads[i].t.Before(h.heads[j].t)
+}
+
+func (h *rowHeap) Swap(i, j int) { h.heads[i], h.heads[j] = h.heads[j], h.heads[i] }
+
+func (h *rowHeap) Push(x interface{}) { h.heads = append(h.heads, x.(rowHead)) }
+
+func (h *rowHeap) Pop() interface{} {
+	last := h.heads[len(h.heads)-1]
+	h.heads = h.heads[:len(h.heads)-1]
+	return last
+}
@@ -0,0 +1,52 @@
+package fake
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+func ExampleEngine() {
+	start := time.Date(2020, 2, 7, 0, 0, 0, 0, time.UTC)
+	e := &Engine{Workers: 4, Batch: 2}
+
+	for i, increment := range []int{1000, 1500, 2500} {
+		id := fmt.Sprintf("host-%v", i)
+		ft, _ := NewTime(id, start, increment, 0, 0, false)
+		fd, _ := NewDataFromConfig(NewDataConfig(id, 100, WithRange(float64(i*10), float64(i*10)), WithSlope(1)))
+		e.Series = append(e.Series, &EngineSeries{ID: id, Time: ft, Data: fd})
+	}
+
+	for row := range e.Run(context.Background(), 3) {
+		fmt.Printf("%v %v %v %v\n", row.Time.Format("15:04:05.0"), row.Series, row.Index, row.Value)
+	}
+	// Output:
+	// 00:00:00.0 host-0 0 0
+	// 00:00:00.0 host-1 0 10
+	// 00:00:00.0 host-2 0 20
+	// 00:00:01.0 host-0 1 1
+	// 00:00:01.5 host-1 1 11
+	// 00:00:02.0 host-0 2 2
+	// 00:00:02.5 host-2 1 21
+	// 00:00:03.0 host-1 2 12
+	// 00:00:05.0 host-2 2 22
+}
+
+// Running an engine again continues where the previous run stopped.
+func ExampleEngine_Run() {
+	ft, _ := NewTime("host", time.Date(2020, 2, 7, 0, 0, 0, 0, time.UTC), 1000, 0, 0, false)
+	fd, _ := NewDataFromConfig(NewDataConfig("host", 100, WithRange(0, 0), WithSlope(1)))
+	e := &Engine{Series: []*EngineSeries{{ID: "host", Time: ft, Data: fd}}, Batch: 2}
+
+	for _, samples := range []int64{5, 5, 2} {
+		var rows []string
+		for row := range e.Run(context.Background(), samples) {
+			rows = append(rows, fmt.Sprintf("%v=%v@%v", row.Index, row.Value, row.Time.Format("15:04:05")))
+		}
+		fmt.Println(rows)
+	}
+	// Output:
+	// [0=0@00:00:00 1=1@00:00:01 2=2@00:00:02 3=3@00:00:03 4=4@00:00:04]
+	// [5=5@00:00:05 6=6@00:00:06 7=7@00:00:07 8=8@00:00:08 9=9@00:00:09]
+	// [10=10@00:00:10 11=11@00:00:11]
+}
@@ -0,0 +1,30 @@
+package fake
+
+// Gate is a Value that decides whether a sample (or the data in it) is "good"
+// or "bad". Pattern, Random and BurstRandom are gates and any of them can be
+// combined into another gate with a Combinator.
+type Gate interface {
+	Value
+	Snapshotter
+
+	// ID returns the unique id of the gate.
+	ID() string
+
+	// Good returns whether the current value is "good".
+	Good() bool
+
+	// Bad returns whether the current value is "bad".
+	Bad() bool
+}
+
+// AllGood returns whether every gate is "good", e.g. to decide whether a
+// sample is kept. It is true when there are no gates.
+func AllGood(gates ...Gate) bool {
+	for _, g := range gates {
+		if g.Bad() {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,17 @@
+package fake
+
+import "fmt"
+
+func ExampleAllGood() {
+	fp, _ := NewPattern("fakePattern", 2, 1, false)
+	fr, _ := NewRandom("fakeRandom", 4, 0.5, false)
+
+	for i := 0; i < 6; i++ {
+		fmt.Print(AllGood(fp, fr), " ")
+		fp.Next()
+		fr.Next()
+	}
+	fmt.Println(AllGood())
+	// Output:
+	// true true false false true false true
+}
@@ -0,0 +1,57 @@
+package fake
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func countGood(g Generator[bool], buf []bool, batches int) int {
+	good := 0
+	for b := 0; b < batches; b++ {
+		for _, v := range buf[:g.Fill(buf)] {
+			if v {
+				good++
+			}
+		}
+	}
+
+	return good
+}
+
+func ExampleGenerator() {
+	fp, _ := NewPattern("fakePattern5", 23, 1, false)
+	fr, _ := NewRandom("fakeRandom5", 4, 0.95, false)
+	buf := make([]bool, 240)
+
+	fmt.Println(countGood(fp, buf, 10), countGood(fr, buf, 10))
+	// Output: 2300 2285
+}
+
+func TestFillAllocs(t *testing.T) {
+	for _, keepStats := range []bool{false, true} {
+		fp, _ := NewPattern("fakePattern", 23, 1, keepStats)
+		fr, _ := NewRandom("fakeRandom", 4, 0.95, keepStats)
+		opts := []DataOption{WithRandom(1, 0.5), WithSpike(100, 10, 150, 5), WithSeasonality(50, 200)}
+		if keepStats {
+			opts = append(opts, WithStats())
+		}
+		fd, _ := NewDataFromConfig(NewDataConfig("fakeData", 1000, opts...))
+		ft, _ := NewTime("fakeTime", time.Date(2020, 2, 7, 0, 0, 0, 0, time.UTC), 60000, 1000, 0, keepStats)
+
+		bools := make([]bool, 1000)
+		floats := make([]float64, 1000)
+		times := make([]time.Time, 1000)
+
+		for name, fill := range map[string]func(){
+			"Pattern": func() { fp.Fill(bools) },
+			"Random":  func() { fr.Fill(bools) },
+			"Data":    func() { fd.Fill(floats) },
+			"Time":    func() { ft.Fill(times) },
+		} {
+			if allocs := testing.AllocsPerRun(10, fill); allocs != 0 {
+				t.Errorf("%v.Fill with keepStats %v allocated %v times, want 0", name, keepStats, allocs)
+			}
+		}
+	}
+}
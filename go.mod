@@ -1,8 +1,9 @@
 module github.com/powerpu/go-fake-ts
 
-go 1.13
+go 1.23
 
 require (
-	github.com/jhorwit2/running-statistics v0.0.0-20151124173315-6b40e26f5051 // indirect
-	github.com/jhorwit2/simple-regression v0.0.0-20151124173209-d8ced50f7321
+	github.com/golang/snappy v1.0.0
+	golang.org/x/image v0.24.0
+	gopkg.in/yaml.v3 v3.0.1
 )
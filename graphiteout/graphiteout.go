@@ -0,0 +1,177 @@
+// Package graphiteout writes fake time series in the Graphite plaintext
+// protocol.
+//
+// An Encoder takes a Time and a set of series and writes one line per series
+// and sample, either to any io.Writer or straight to Carbon:
+//
+//  conn, err := net.Dial("tcp", "localhost:2003")
+//  e := graphiteout.NewEncoder(conn, fakeTime,
+//      graphiteout.Series{Path: "servers.host-1.cpu", Value: fakeData1},
+//      graphiteout.Series{Path: "servers.host-1.memory", Value: fakeData2})
+//  err = e.Encode(1000)
+//
+// which may look as follows:
+//
+//  servers.host-1.cpu 23.5 1581039550
+//  servers.host-1.memory 97.2 1581039550
+//  ...etc...
+//
+// A series whose gate is "bad" (or any sample while a gate of the Encoder is
+// "bad") is left out.
+package graphiteout
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	fake "github.com/powerpu/go-fake-ts"
+)
+
+// Series is a single named series of an Encoder.
+type Series struct {
+	// The dot separated path of the series, e.g. "servers.host-1.cpu".
+	Path string
+
+	// Graphite tags of the series, appended as ";name=value" sorted by name.
+	// Optional.
+	Tags map[string]string
+
+	// The value of the series, usually a Data but any Value returning a
+	// number will do.
+	Value fake.Value
+
+	// Decide whether the data of the series is "good". Optional.
+	Gates []fake.Gate
+}
+
+// Encoder writes a Time and a set of series in the plaintext protocol. Every
+// call to Encode advances the Time, the gates and all series in lock-step so
+// none of them may appear twice or be used elsewhere while encoding.
+//
+// An Encoder must be created with NewEncoder. The zero value has nowhere to
+// write to.
+type Encoder struct {
+	// The timestamp of every line.
+	Time *fake.Time
+
+	// Decide whether a sample is "good" for all series. Optional.
+	Gates []fake.Gate
+
+	// The series to write.
+	Series []Series
+
+	w io.Writer
+}
+
+// NewEncoder creates an Encoder writing to w.
+func NewEncoder(w io.Writer, ft *fake.Time, series ...Series) *Encoder {
+	return &Encoder{Time: ft, Series: series, w: w}
+}
+
+// Encode writes the lines of the next count samples. The lines of every
+// sample are written to the underlying io.Writer at once.
+func (e *Encoder) Encode(count int) error {
+	if e.w == nil {
+		return errors.New("graphiteout: an Encoder must be created with NewEncoder")
+	}
+
+	paths := make([]string, len(e.Series))
+	for i, s := range e.Series {
+		paths[i] = path(s)
+	}
+
+	var buf bytes.Buffer
+	for n := 0; n < count; n++ {
+		buf.Reset()
+
+		if fake.AllGood(e.Gates...) {
+			ts := strconv.FormatInt(e.Time.Time().Unix(), 10)
+			for i, s := range e.Series {
+				if !fake.AllGood(s.Gates...) {
+					continue
+				}
+
+				v, ok := formatValue(s.Value.Val())
+				if !ok {
+					continue
+				}
+
+				buf.WriteString(paths[i] + " " + v + " " + ts + "\n")
+			}
+		}
+
+		if buf.Len() > 0 {
+			if _, err := e.w.Write(buf.Bytes()); err != nil {
+				return err
+			}
+		}
+
+		e.next()
+	}
+
+	return nil
+}
+
+// next advances every generator by one sample.
+func (e *Encoder) next() {
+	e.Time.Next()
+
+	for _, g := range e.Gates {
+		g.Next()
+	}
+
+	for _, s := range e.Series {
+		s.Value.Next()
+		for _, g := range s.Gates {
+			g.Next()
+		}
+	}
+}
+
+// Spaces would end the path early
+var pathEscaper = strings.NewReplacer(" ", "_", "\n", "_")
+
+// path returns the path of a series with its tags.
+func path(s Series) string {
+	p := pathEscaper.Replace(s.Path)
+
+	names := make([]string, 0, len(s.Tags))
+	for name := range s.Tags {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		p += ";" + pathEscaper.Replace(name) + "=" + pathEscaper.Replace(s.Tags[name])
+	}
+
+	return p
+}
+
+// formatValue formats a value and returns whether it can be written at all.
+func formatValue(v interface{}) (string, bool) {
+	switch v := v.(type) {
+	case float64:
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return "", false
+		}
+
+		return strconv.FormatFloat(v, 'f', -1, 64), true
+	case bool:
+		if v {
+			return "1", true
+		}
+
+		return "0", true
+	case int, int64:
+		return fmt.Sprint(v), true
+	}
+
+	return "", false
+}
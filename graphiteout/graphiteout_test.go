@@ -0,0 +1,70 @@
+package graphiteout
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"os"
+	"time"
+
+	fake "github.com/powerpu/go-fake-ts"
+)
+
+func ExampleEncoder() {
+	ft, _ := fake.NewTime("ts", time.Date(2020, 2, 7, 0, 0, 0, 0, time.UTC), 60000, 0, 0, false)
+	fp, _ := fake.NewPattern("outage", 2, 1, false)
+	cpu, _ := fake.NewDataFromConfig(fake.NewDataConfig("cpu", 4, fake.WithSlope(1)))
+	mem, _ := fake.NewDataFromConfig(fake.NewDataConfig("memory", 4, fake.WithRange(0, 10)))
+
+	e := NewEncoder(os.Stdout, ft,
+		Series{Path: "servers.host 1.cpu", Value: cpu},
+		Series{Path: "servers.host-1.memory", Tags: map[string]string{"dc": "eu", "app": "db"}, Value: mem, Gates: []fake.Gate{fp}})
+	e.Encode(4)
+	// Output:
+	// servers.host_1.cpu 50 1581033600
+	// servers.host-1.memory;app=db;dc=eu 5 1581033600
+	// servers.host_1.cpu 51 1581033660
+	// servers.host-1.memory;app=db;dc=eu 5 1581033660
+	// servers.host_1.cpu 52 1581033720
+	// servers.host_1.cpu 53 1581033780
+	// servers.host-1.memory;app=db;dc=eu 5 1581033780
+}
+
+func ExampleEncoder_tcp() {
+	// A local stand-in for Carbon
+	l, _ := net.Listen("tcp", "127.0.0.1:0")
+	defer l.Close()
+
+	received := make(chan []string)
+	go func() {
+		conn, _ := l.Accept()
+		defer conn.Close()
+
+		var lines []string
+		for s := bufio.NewScanner(conn); s.Scan(); {
+			lines = append(lines, s.Text())
+		}
+		received <- lines
+	}()
+
+	ft, _ := fake.NewTime("ts", time.Date(2020, 2, 7, 0, 0, 0, 0, time.UTC), 60000, 0, 0, false)
+	cpu, _ := fake.NewDataFromConfig(fake.NewDataConfig("cpu", 3, fake.WithSlope(1)))
+
+	conn, _ := net.Dial("tcp", l.Addr().String())
+	NewEncoder(conn, ft, Series{Path: "servers.host-1.cpu", Value: cpu}).Encode(3)
+	conn.Close()
+
+	for _, line := range <-received {
+		fmt.Println(line)
+	}
+	// Output:
+	// servers.host-1.cpu 50 1581033600
+	// servers.host-1.cpu 51 1581033660
+	// servers.host-1.cpu 52 1581033720
+}
+
+func ExampleEncoder_zero() {
+	var e Encoder
+	fmt.Println(e.Encode(1))
+	// Output: graphiteout: an Encoder must be created with NewEncoder
+}
@@ -0,0 +1,308 @@
+// Package influxout encodes fake time series as InfluxDB line protocol.
+//
+// An Encoder takes a Time and a set of tagged series and writes one line per
+// series and sample:
+//
+//  e := influxout.NewEncoder(os.Stdout, fakeTime, influxout.Series{
+//      Measurement: "system",
+//      Tags:        map[string]string{"host": "server 01"},
+//      Fields: []influxout.Field{
+//          {Key: "cpu", Value: fakeData1, Gates: []fake.Gate{fakeRandom}},
+//          {Key: "memory", Value: fakeData2},
+//      },
+//  })
+//  e.Gates = []fake.Gate{fakePattern}
+//  e.Precision = influxout.Second
+//  err := e.Encode(1000)
+//
+// which may look as follows:
+//
+//  system,host=server\ 01 cpu=23.5,memory=97.2 1581039550
+//  system,host=server\ 01 memory=84.9 1581039650
+//  ...etc...
+//
+// A "bad sample" (a gate of the Encoder or the series is bad) drops the whole
+// line and "bad data" (a gate of a field is bad) drops the field. A line
+// without any fields left is dropped too.
+package influxout
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	fake "github.com/powerpu/go-fake-ts"
+)
+
+// Precision is the unit of the timestamps.
+type Precision int
+
+const (
+	// Nanosecond timestamps, the default of InfluxDB.
+	Nanosecond Precision = iota
+
+	// Microsecond timestamps.
+	Microsecond
+
+	// Millisecond timestamps.
+	Millisecond
+
+	// Second timestamps.
+	Second
+)
+
+// Series is a measurement with a tag set and its fields. Every series is
+// written as a separate line.
+type Series struct {
+	// The name of the measurement.
+	Measurement string
+
+	// The tag set of every line. Tags are written sorted by key.
+	Tags map[string]string
+
+	// The field set of every line.
+	Fields []Field
+
+	// Decide whether a sample of the series is "good". Optional.
+	Gates []fake.Gate
+}
+
+// Field is a single field of a Series.
+type Field struct {
+	// The key of the field.
+	Key string
+
+	// The value of the field, usually a Data but any Value will do. Floats
+	// are written as floats, bools as booleans, ints with an "i" suffix and
+	// anything else as a string.
+	Value fake.Value
+
+	// Decide whether the data of the field is "good". Optional.
+	Gates []fake.Gate
+}
+
+// Encoder writes a Time and a set of series as line protocol. Every call to
+// Encode advances the Time, the gates and all fields in lock-step so none of
+// them may appear twice or be used elsewhere while encoding.
+//
+// An Encoder must be created with NewEncoder. The zero value has nowhere to
+// write to.
+type Encoder struct {
+	// The timestamp of every line.
+	Time *fake.Time
+
+	// Decide whether a sample is "good" for all series. Optional.
+	Gates []fake.Gate
+
+	// The series to write.
+	Series []Series
+
+	// The unit of the timestamps. Defaults to Nanosecond.
+	Precision Precision
+
+	// Number of lines written to the underlying io.Writer at a time.
+	// Defaults to 5000.
+	BatchSize int
+
+	w io.Writer
+}
+
+// NewEncoder creates an Encoder writing to w with all defaults set.
+func NewEncoder(w io.Writer, ft *fake.Time, series ...Series) *Encoder {
+	return &Encoder{
+		Time:      ft,
+		Series:    series,
+		Precision: Nanosecond,
+		BatchSize: 5000,
+		w:         w,
+	}
+}
+
+// Encode writes the lines of the next count samples. Lines are written to
+// the underlying io.Writer in batches of BatchSize lines, the last batch
+// may be shorter.
+func (e *Encoder) Encode(count int) error {
+	if e.w == nil {
+		return errors.New("influxout: an Encoder must be created with NewEncoder")
+	}
+
+	batchSize := e.BatchSize
+	if batchSize <= 0 {
+		batchSize = 5000
+	}
+
+	// Sorting and escaping the tags only needs to happen once
+	prefixes := make([][]byte, len(e.Series))
+	for i, s := range e.Series {
+		prefixes[i] = appendPrefix(nil, s)
+	}
+
+	var buf bytes.Buffer
+	lines := 0
+	for i := 0; i < count; i++ {
+		if fake.AllGood(e.Gates...) {
+			ts := e.timestamp(e.Time.Time())
+			for j, s := range e.Series {
+				if e.appendLine(&buf, prefixes[j], s, ts) {
+					lines++
+				}
+
+				if lines == batchSize {
+					if _, err := e.w.Write(buf.Bytes()); err != nil {
+						return err
+					}
+
+					buf.Reset()
+					lines = 0
+				}
+			}
+		}
+
+		e.next()
+	}
+
+	if lines > 0 {
+		_, err := e.w.Write(buf.Bytes())
+		return err
+	}
+
+	return nil
+}
+
+// appendLine writes the line of the current sample of a series to buf and
+// returns whether there was one.
+func (e *Encoder) appendLine(buf *bytes.Buffer, prefix []byte, s Series, ts string) bool {
+	if !fake.AllGood(s.Gates...) {
+		return false
+	}
+
+	start := buf.Len()
+	buf.Write(prefix)
+
+	sep := byte(' ')
+	for _, f := range s.Fields {
+		if !fake.AllGood(f.Gates...) {
+			continue
+		}
+
+		v, ok := formatValue(f.Value.Val())
+		if !ok {
+			continue
+		}
+
+		buf.WriteByte(sep)
+		buf.WriteString(escape(f.Key, keyEscaper))
+		buf.WriteByte('=')
+		buf.WriteString(v)
+		sep = ','
+	}
+
+	// A line needs at least one field
+	if sep == ' ' {
+		buf.Truncate(start)
+		return false
+	}
+
+	buf.WriteByte(' ')
+	buf.WriteString(ts)
+	buf.WriteByte('\n')
+	return true
+}
+
+// next advances every generator by one sample.
+func (e *Encoder) next() {
+	e.Time.Next()
+
+	for _, g := range e.Gates {
+		g.Next()
+	}
+
+	for _, s := range e.Series {
+		for _, g := range s.Gates {
+			g.Next()
+		}
+
+		for _, f := range s.Fields {
+			f.Value.Next()
+			for _, g := range f.Gates {
+				g.Next()
+			}
+		}
+	}
+}
+
+// timestamp formats a timestamp in the precision of the encoder.
+func (e *Encoder) timestamp(t time.Time) string {
+	switch e.Precision {
+	case Microsecond:
+		return strconv.FormatInt(t.UnixMicro(), 10)
+	case Millisecond:
+		return strconv.FormatInt(t.UnixMilli(), 10)
+	case Second:
+		return strconv.FormatInt(t.Unix(), 10)
+	}
+
+	return strconv.FormatInt(t.UnixNano(), 10)
+}
+
+var (
+	measurementEscaper = strings.NewReplacer(",", `\,`, " ", `\ `)
+	keyEscaper         = strings.NewReplacer(",", `\,`, "=", `\=`, " ", `\ `)
+	stringEscaper      = strings.NewReplacer(`"`, `\"`, `\`, `\\`)
+)
+
+func escape(s string, r *strings.Replacer) string {
+	return r.Replace(s)
+}
+
+// appendPrefix appends the escaped measurement and tag set of a series.
+func appendPrefix(dst []byte, s Series) []byte {
+	dst = append(dst, escape(s.Measurement, measurementEscaper)...)
+
+	keys := make([]string, 0, len(s.Tags))
+	for k := range s.Tags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		// Empty tag values are not allowed
+		if s.Tags[k] == "" {
+			continue
+		}
+
+		dst = append(dst, ',')
+		dst = append(dst, escape(k, keyEscaper)...)
+		dst = append(dst, '=')
+		dst = append(dst, escape(s.Tags[k], keyEscaper)...)
+	}
+
+	return dst
+}
+
+// formatValue formats a field value and returns whether it can be written at
+// all. Line protocol has no way to write NaN or infinity.
+func formatValue(v interface{}) (string, bool) {
+	switch v := v.(type) {
+	case float64:
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return "", false
+		}
+
+		return strconv.FormatFloat(v, 'f', -1, 64), true
+	case bool:
+		return strconv.FormatBool(v), true
+	case int:
+		return strconv.Itoa(v) + "i", true
+	case int64:
+		return strconv.FormatInt(v, 10) + "i", true
+	}
+
+	return `"` + escape(fmt.Sprint(v), stringEscaper) + `"`, true
+}
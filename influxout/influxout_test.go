@@ -0,0 +1,64 @@
+package influxout
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	fake "github.com/powerpu/go-fake-ts"
+)
+
+func ExampleEncoder() {
+	ft, _ := fake.NewTime("ts", time.Date(2020, 2, 7, 0, 0, 0, 0, time.UTC), 60000, 0, 0, false)
+	fp, _ := fake.NewPattern("outage", 3, 1, false)
+	fr, _ := fake.NewRandom("cpuGate", 4, 0.7, false)
+	cpu, _ := fake.NewDataFromConfig(fake.NewDataConfig("cpu", 8, fake.WithRange(0, 100), fake.WithBump(80), fake.WithRandom(1, 0.5)))
+	mem, _ := fake.NewDataFromConfig(fake.NewDataConfig("memory", 8, fake.WithRange(0, 100), fake.WithSlope(1)))
+
+	e := NewEncoder(os.Stdout, ft, Series{
+		Measurement: "system load",
+		Tags:        map[string]string{"region": "eu,west", "host": "server 01"},
+		Fields: []Field{
+			{Key: "cpu", Value: cpu, Gates: []fake.Gate{fr}},
+			{Key: "memory", Value: mem},
+		},
+	})
+	e.Gates = []fake.Gate{fp}
+	e.Precision = Second
+	e.Encode(8)
+	// Output:
+	// system\ load,host=server\ 01,region=eu\,west cpu=43.080621974368356,memory=50 1581033600
+	// system\ load,host=server\ 01,region=eu\,west cpu=38.80532460387041,memory=51 1581033660
+	// system\ load,host=server\ 01,region=eu\,west cpu=38.20869414548473,memory=52 1581033720
+	// system\ load,host=server\ 01,region=eu\,west cpu=38.419299969237734,memory=54 1581033840
+	// system\ load,host=server\ 01,region=eu\,west cpu=37.65031543656667,memory=55 1581033900
+	// system\ load,host=server\ 01,region=eu\,west memory=56 1581033960
+}
+
+// batchWriter prints every batch it receives.
+type batchWriter struct{}
+
+func (batchWriter) Write(p []byte) (int, error) {
+	fmt.Printf("batch of %v lines\n", strings.Count(string(p), "\n"))
+	return len(p), nil
+}
+
+func ExampleEncoder_batches() {
+	ft, _ := fake.NewTime("ts", time.Date(2020, 2, 7, 0, 0, 0, 0, time.UTC), 1000, 0, 0, false)
+	fd, _ := fake.NewDataFromConfig(fake.NewDataConfig("cpu", 10, fake.WithSlope(1)))
+
+	e := NewEncoder(batchWriter{}, ft, Series{Measurement: "cpu", Fields: []Field{{Key: "value", Value: fd}}})
+	e.BatchSize = 4
+	e.Encode(10)
+	// Output:
+	// batch of 4 lines
+	// batch of 4 lines
+	// batch of 2 lines
+}
+
+func ExampleEncoder_zero() {
+	var e Encoder
+	fmt.Println(e.Encode(1))
+	// Output: influxout: an Encoder must be created with NewEncoder
+}
@@ -0,0 +1,404 @@
+// Package jsonlout writes fake time series as JSON Lines in any shape.
+//
+// Every row is rendered either from a text/template or from a field mapping.
+// A template is executed with a Row and its output must be a JSON value,
+// which is compacted onto a single line:
+//
+//  w, err := jsonlout.NewTemplateWriter(os.Stdout,
+//      `{{if .Good}}{"ts": {{unixMilli .Time}}, "cpu": {{json .Values.cpu}}}{{end}}`,
+//      fakeTime, jsonlout.Column{Name: "cpu", Value: fakeData1})
+//  err = w.Write(1000)
+//
+// A row whose template renders nothing but white space is skipped. A field
+// mapping lists the keys of every object and where their values come from,
+// keys with dots are nested:
+//
+//  w, err := jsonlout.NewMappingWriter(os.Stdout, []jsonlout.Mapping{
+//      {Key: "timestamp", From: "time.unix"},
+//      {Key: "metrics.cpu", From: "cpu"},
+//      {Key: "metrics.cpuValid", From: "cpu.good"},
+//  }, fakeTime, jsonlout.Column{Name: "cpu", Value: fakeData1})
+//
+// which may look as follows:
+//
+//  {"timestamp":1581039550,"metrics":{"cpu":23.5,"cpuValid":true}}
+//  {"timestamp":1581039650,"metrics":{"cpu":null,"cpuValid":false}}
+//  ...etc...
+package jsonlout
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"text/template"
+	"time"
+
+	fake "github.com/powerpu/go-fake-ts"
+)
+
+// Column is a single named value of a Writer.
+type Column struct {
+	// The name of the column.
+	Name string
+
+	// The value of the column, usually a Data but any Value will do.
+	Value fake.Value
+
+	// Decide whether the data of the column is "good". Optional.
+	Gates []fake.Gate
+
+	// Labels of the series, e.g. the host it belongs to. Optional.
+	Labels map[string]string
+}
+
+// Row is a single sample passed to a template.
+type Row struct {
+	// Position of the row, starting at 0.
+	Index int64
+
+	// The time of the sample.
+	Time time.Time
+
+	// Whether every sample gate is "good".
+	Good bool
+
+	// The value of every column in the order they were defined.
+	Fields []Field
+
+	// The value of every column by name.
+	Values map[string]interface{}
+}
+
+// Field is the value of a single column in a Row.
+type Field struct {
+	// The name of the column.
+	Name string
+
+	// The current value of the column.
+	Value interface{}
+
+	// Whether every data gate of the column is "good".
+	Good bool
+
+	// The labels of the column.
+	Labels map[string]string
+}
+
+// Field returns the field of the named column or an empty Field if there is
+// none.
+func (r Row) Field(name string) Field {
+	for _, f := range r.Fields {
+		if f.Name == name {
+			return f
+		}
+	}
+
+	return Field{}
+}
+
+// Mapping maps a key of the output to where its value comes from. From is
+// one of:
+//
+//  index                the position of the row
+//  good                 whether every sample gate is "good"
+//  time                 the timestamp as an RFC 3339 string
+//  time.unix            the timestamp in seconds since 1970-01-01 UTC
+//  time.unixMilli       the timestamp in milliseconds
+//  time.unixNano        the timestamp in nanoseconds
+//  <column>             the value of a column, null while its data is bad
+//  <column>.good        whether every data gate of a column is "good"
+//  <column>.labels      all labels of a column
+//  <column>.labels.<l>  a single label of a column
+type Mapping struct {
+	Key  string
+	From string
+}
+
+// Writer writes a Time and a set of columns as JSON Lines. Every call to
+// Write advances the Time, the gates and all columns in lock-step so none of
+// them may appear twice or be used elsewhere while writing.
+//
+// A Writer must be created with NewTemplateWriter or NewMappingWriter. The
+// zero value has nowhere to write to.
+type Writer struct {
+	// The timestamp of every row.
+	Time *fake.Time
+
+	// Decide whether a sample is "good". Optional.
+	Gates []fake.Gate
+
+	// The columns of every row.
+	Columns []Column
+
+	tmpl    *template.Template
+	mapping []Mapping
+	w       *bufio.Writer
+	i       int64
+}
+
+// Funcs are the functions available to templates:
+//
+//  json       the JSON encoding of any value
+//  unix       a timestamp in seconds since 1970-01-01 UTC
+//  unixMilli  a timestamp in milliseconds
+//  unixNano   a timestamp in nanoseconds
+var Funcs = template.FuncMap{
+	"json": func(v interface{}) (string, error) {
+		b, err := json.Marshal(v)
+		return string(b), err
+	},
+	"unix":      func(t time.Time) int64 { return t.Unix() },
+	"unixMilli": func(t time.Time) int64 { return t.UnixMilli() },
+	"unixNano":  func(t time.Time) int64 { return t.UnixNano() },
+}
+
+// NewTemplateWriter creates a Writer rendering every row with a template.
+func NewTemplateWriter(w io.Writer, text string, ft *fake.Time, columns ...Column) (*Writer, error) {
+	tmpl, err := template.New("row").Funcs(Funcs).Parse(text)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Writer{Time: ft, Columns: columns, tmpl: tmpl, w: bufio.NewWriter(w)}, nil
+}
+
+// NewMappingWriter creates a Writer rendering every row as an object with
+// the given keys. Every source is checked against the columns first.
+func NewMappingWriter(w io.Writer, mapping []Mapping, ft *fake.Time, columns ...Column) (*Writer, error) {
+	names := map[string]bool{}
+	for _, c := range columns {
+		names[c.Name] = true
+	}
+
+	for i, m := range mapping {
+		if m.Key == "" {
+			return nil, errors.New("key of the mapping from '" + m.From + "' cannot be blank")
+		}
+
+		// A key cannot hold a value and an object at the same time
+		for _, other := range mapping[:i] {
+			if m.Key == other.Key || strings.HasPrefix(m.Key, other.Key+".") || strings.HasPrefix(other.Key, m.Key+".") {
+				return nil, errors.New("key '" + m.Key + "' clashes with key '" + other.Key + "'")
+			}
+		}
+
+		if !validSource(m.From, names) {
+			return nil, errors.New("source '" + m.From + "' of key '" + m.Key + "' is not a known column or field")
+		}
+	}
+
+	return &Writer{Time: ft, Columns: columns, mapping: mapping, w: bufio.NewWriter(w)}, nil
+}
+
+// validSource returns whether a mapping source can be resolved.
+func validSource(from string, names map[string]bool) bool {
+	switch from {
+	case "index", "good", "time", "time.unix", "time.unixMilli", "time.unixNano":
+		return true
+	}
+
+	name, rest := column(from, names)
+	if name == "" {
+		return false
+	}
+
+	return rest == "" || rest == "good" || rest == "labels" || strings.HasPrefix(rest, "labels.")
+}
+
+// column splits a source into the longest column name it starts with and
+// the rest, so column names may contain dots themselves.
+func column(from string, names map[string]bool) (string, string) {
+	for i := len(from); i > 0; i-- {
+		if i < len(from) && from[i] != '.' {
+			continue
+		}
+
+		if names[from[:i]] {
+			return from[:i], strings.TrimPrefix(from[i:], ".")
+		}
+	}
+
+	return "", ""
+}
+
+// Write writes the next count samples and flushes them to the underlying
+// io.Writer. Skipped rows are counted too.
+func (jw *Writer) Write(count int) error {
+	if jw.w == nil {
+		return errors.New("jsonlout: a Writer must be created with NewTemplateWriter or NewMappingWriter")
+	}
+
+	var buf, line bytes.Buffer
+	for n := 0; n < count; n++ {
+		row := jw.row()
+
+		buf.Reset()
+		if jw.tmpl != nil {
+			if err := jw.tmpl.Execute(&buf, row); err != nil {
+				return err
+			}
+		} else {
+			jw.writeMapping(&buf, row)
+		}
+
+		jw.next()
+
+		if len(bytes.TrimSpace(buf.Bytes())) == 0 {
+			continue
+		}
+
+		line.Reset()
+		if err := json.Compact(&line, buf.Bytes()); err != nil {
+			return errors.New("row " + buf.String() + " is not valid JSON: " + err.Error())
+		}
+
+		line.WriteByte('\n')
+		if _, err := jw.w.Write(line.Bytes()); err != nil {
+			return err
+		}
+	}
+
+	return jw.w.Flush()
+}
+
+// row collects the current sample.
+func (jw *Writer) row() Row {
+	row := Row{
+		Index:  jw.i,
+		Time:   jw.Time.Time(),
+		Good:   fake.AllGood(jw.Gates...),
+		Fields: make([]Field, len(jw.Columns)),
+		Values: make(map[string]interface{}, len(jw.Columns)),
+	}
+
+	for i, c := range jw.Columns {
+		row.Fields[i] = Field{Name: c.Name, Value: c.Value.Val(), Good: fake.AllGood(c.Gates...), Labels: c.Labels}
+		row.Values[c.Name] = row.Fields[i].Value
+	}
+
+	return row
+}
+
+// next advances every generator by one sample.
+func (jw *Writer) next() {
+	jw.Time.Next()
+
+	for _, g := range jw.Gates {
+		g.Next()
+	}
+
+	for _, c := range jw.Columns {
+		c.Value.Next()
+		for _, g := range c.Gates {
+			g.Next()
+		}
+	}
+
+	jw.i++
+}
+
+// writeMapping writes a row as an object following the mapping.
+func (jw *Writer) writeMapping(buf *bytes.Buffer, row Row) {
+	names := make(map[string]bool, len(row.Fields))
+	for _, f := range row.Fields {
+		names[f.Name] = true
+	}
+
+	keys := make([][]string, len(jw.mapping))
+	for i, m := range jw.mapping {
+		keys[i] = strings.Split(m.Key, ".")
+	}
+
+	var write func(entries []int, depth int)
+	write = func(entries []int, depth int) {
+		buf.WriteByte('{')
+
+		// Group entries by their key at this depth in order of appearance
+		var order []string
+		groups := map[string][]int{}
+		for _, e := range entries {
+			k := keys[e][depth]
+			if _, ok := groups[k]; !ok {
+				order = append(order, k)
+			}
+
+			groups[k] = append(groups[k], e)
+		}
+
+		for i, k := range order {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+
+			b, _ := json.Marshal(k)
+			buf.Write(b)
+			buf.WriteByte(':')
+
+			group := groups[k]
+			if len(keys[group[0]]) == depth+1 {
+				b, err := json.Marshal(resolve(jw.mapping[group[0]].From, row, names))
+				if err != nil {
+					b = []byte("null")
+				}
+
+				buf.Write(b)
+				continue
+			}
+
+			write(group, depth+1)
+		}
+
+		buf.WriteByte('}')
+	}
+
+	entries := make([]int, len(jw.mapping))
+	for i := range entries {
+		entries[i] = i
+	}
+
+	write(entries, 0)
+}
+
+// resolve returns the value of a mapping source.
+func resolve(from string, row Row, names map[string]bool) interface{} {
+	switch from {
+	case "index":
+		return row.Index
+	case "good":
+		return row.Good
+	case "time":
+		return row.Time
+	case "time.unix":
+		return row.Time.Unix()
+	case "time.unixMilli":
+		return row.Time.UnixMilli()
+	case "time.unixNano":
+		return row.Time.UnixNano()
+	}
+
+	name, rest := column(from, names)
+	f := row.Field(name)
+
+	switch {
+	case rest == "":
+		if !f.Good {
+			return nil
+		}
+
+		return f.Value
+	case rest == "good":
+		return f.Good
+	case rest == "labels":
+		return f.Labels
+	}
+
+	label, ok := f.Labels[strings.TrimPrefix(rest, "labels.")]
+	if !ok {
+		return nil
+	}
+
+	return label
+}
@@ -0,0 +1,64 @@
+package jsonlout
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	fake "github.com/powerpu/go-fake-ts"
+)
+
+func ExampleNewTemplateWriter() {
+	ft, _ := fake.NewTime("ts", time.Date(2020, 2, 7, 0, 0, 0, 0, time.UTC), 60000, 0, 0, false)
+	fp, _ := fake.NewPattern("outage", 2, 1, false)
+	fr, _ := fake.NewRandom("cpuGate", 4, 0.5, false)
+	cpu, _ := fake.NewDataFromConfig(fake.NewDataConfig("cpu", 5, fake.WithSlope(1)))
+	mem, _ := fake.NewDataFromConfig(fake.NewDataConfig("memory", 5, fake.WithSlope(-1)))
+
+	w, _ := NewTemplateWriter(os.Stdout, `{{if .Good}}{
+		"ts": {{unixMilli .Time}},
+		"host": {{json (.Field "cpu").Labels.host}},
+		"metrics": [{{range $i, $f := .Fields}}{{if $i}},{{end}}
+			{"name": {{json $f.Name}}, "value": {{if $f.Good}}{{json $f.Value}}{{else}}null{{end}}}{{end}}
+		]
+	}{{end}}`, ft,
+		Column{Name: "cpu", Value: cpu, Gates: []fake.Gate{fr}, Labels: map[string]string{"host": "host-1"}},
+		Column{Name: "memory", Value: mem})
+	w.Gates = []fake.Gate{fp}
+	w.Write(5)
+	// Output:
+	// {"ts":1581033600000,"host":"host-1","metrics":[{"name":"cpu","value":50},{"name":"memory","value":50}]}
+	// {"ts":1581033660000,"host":"host-1","metrics":[{"name":"cpu","value":51},{"name":"memory","value":49}]}
+	// {"ts":1581033780000,"host":"host-1","metrics":[{"name":"cpu","value":null},{"name":"memory","value":47}]}
+	// {"ts":1581033840000,"host":"host-1","metrics":[{"name":"cpu","value":54},{"name":"memory","value":46}]}
+}
+
+func ExampleNewMappingWriter() {
+	ft, _ := fake.NewTime("ts", time.Date(2020, 2, 7, 0, 0, 0, 0, time.UTC), 60000, 0, 0, false)
+	fr, _ := fake.NewRandom("cpuGate", 4, 0.5, false)
+	cpu, _ := fake.NewDataFromConfig(fake.NewDataConfig("cpu", 5, fake.WithSlope(1)))
+
+	w, _ := NewMappingWriter(os.Stdout, []Mapping{
+		{Key: "timestamp", From: "time"},
+		{Key: "host", From: "cpu.labels.host"},
+		{Key: "metrics.cpu", From: "cpu"},
+		{Key: "metrics.cpuValid", From: "cpu.good"},
+	}, ft, Column{Name: "cpu", Value: cpu, Gates: []fake.Gate{fr}, Labels: map[string]string{"host": "host-1"}})
+	w.Write(5)
+
+	_, err := NewMappingWriter(os.Stdout, []Mapping{{Key: "mem", From: "memory"}}, ft, Column{Name: "cpu", Value: cpu})
+	fmt.Println(err)
+	// Output:
+	// {"timestamp":"2020-02-07T00:00:00Z","host":"host-1","metrics":{"cpu":50,"cpuValid":true}}
+	// {"timestamp":"2020-02-07T00:01:00Z","host":"host-1","metrics":{"cpu":51,"cpuValid":true}}
+	// {"timestamp":"2020-02-07T00:02:00Z","host":"host-1","metrics":{"cpu":52,"cpuValid":true}}
+	// {"timestamp":"2020-02-07T00:03:00Z","host":"host-1","metrics":{"cpu":null,"cpuValid":false}}
+	// {"timestamp":"2020-02-07T00:04:00Z","host":"host-1","metrics":{"cpu":54,"cpuValid":true}}
+	// source 'memory' of key 'mem' is not a known column or field
+}
+
+func ExampleWriter_zero() {
+	var w Writer
+	fmt.Println(w.Write(1))
+	// Output: jsonlout: a Writer must be created with NewTemplateWriter or NewMappingWriter
+}
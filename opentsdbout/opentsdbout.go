@@ -0,0 +1,200 @@
+// Package opentsdbout writes fake time series as OpenTSDB telnet style "put"
+// lines.
+//
+// An Encoder takes a Time and a set of tagged series and writes one line per
+// series and sample, either to any io.Writer or straight to a TSD:
+//
+//  conn, err := net.Dial("tcp", "localhost:4242")
+//  e := opentsdbout.NewEncoder(conn, fakeTime, opentsdbout.Series{
+//      Metric: "sys.cpu.user",
+//      Tags:   map[string]string{"host": "host-1"},
+//      Value:  fakeData1,
+//  })
+//  err = e.Encode(1000)
+//
+// which may look as follows:
+//
+//  put sys.cpu.user 1581039550 23.5 host=host-1
+//  put sys.cpu.user 1581039650 26.5 host=host-1
+//  ...etc...
+//
+// A series whose gate is "bad" (or any sample while a gate of the Encoder is
+// "bad") is left out.
+package opentsdbout
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	fake "github.com/powerpu/go-fake-ts"
+)
+
+// Series is a single tagged metric of an Encoder.
+type Series struct {
+	// The name of the metric, e.g. "sys.cpu.user".
+	Metric string
+
+	// The tags of the series. OpenTSDB needs at least one tag.
+	Tags map[string]string
+
+	// The value of the series, usually a Data but any Value returning a
+	// number will do.
+	Value fake.Value
+
+	// Decide whether the data of the series is "good". Optional.
+	Gates []fake.Gate
+}
+
+// Encoder writes a Time and a set of series as "put" lines. Every call to
+// Encode advances the Time, the gates and all series in lock-step so none of
+// them may appear twice or be used elsewhere while encoding.
+//
+// An Encoder must be created with NewEncoder. The zero value has nowhere to
+// write to.
+type Encoder struct {
+	// The timestamp of every line.
+	Time *fake.Time
+
+	// Decide whether a sample is "good" for all series. Optional.
+	Gates []fake.Gate
+
+	// The series to write.
+	Series []Series
+
+	// Write timestamps in milliseconds rather than seconds.
+	Millis bool
+
+	w io.Writer
+}
+
+// NewEncoder creates an Encoder writing to w.
+func NewEncoder(w io.Writer, ft *fake.Time, series ...Series) *Encoder {
+	return &Encoder{Time: ft, Series: series, w: w}
+}
+
+// Encode writes the lines of the next count samples. The lines of every
+// sample are written to the underlying io.Writer at once.
+func (e *Encoder) Encode(count int) error {
+	if e.w == nil {
+		return errors.New("opentsdbout: an Encoder must be created with NewEncoder")
+	}
+
+	tags := make([]string, len(e.Series))
+	for i, s := range e.Series {
+		if len(s.Tags) == 0 {
+			return errors.New("OpenTSDB metric '" + s.Metric + "' needs at least one tag")
+		}
+
+		tags[i] = tagSet(s.Tags)
+	}
+
+	var buf bytes.Buffer
+	for n := 0; n < count; n++ {
+		buf.Reset()
+
+		if fake.AllGood(e.Gates...) {
+			ts := strconv.FormatInt(e.Time.Time().Unix(), 10)
+			if e.Millis {
+				ts = strconv.FormatInt(e.Time.Time().UnixMilli(), 10)
+			}
+
+			for i, s := range e.Series {
+				if !fake.AllGood(s.Gates...) {
+					continue
+				}
+
+				v, ok := formatValue(s.Value.Val())
+				if !ok {
+					continue
+				}
+
+				buf.WriteString("put " + sanitize(s.Metric) + " " + ts + " " + v + " " + tags[i] + "\n")
+			}
+		}
+
+		if buf.Len() > 0 {
+			if _, err := e.w.Write(buf.Bytes()); err != nil {
+				return err
+			}
+		}
+
+		e.next()
+	}
+
+	return nil
+}
+
+// next advances every generator by one sample.
+func (e *Encoder) next() {
+	e.Time.Next()
+
+	for _, g := range e.Gates {
+		g.Next()
+	}
+
+	for _, s := range e.Series {
+		s.Value.Next()
+		for _, g := range s.Gates {
+			g.Next()
+		}
+	}
+}
+
+// sanitize replaces every character OpenTSDB does not allow in metrics and
+// tags with an underscore.
+func sanitize(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		case r == '-' || r == '_' || r == '.' || r == '/':
+			return r
+		}
+
+		return '_'
+	}, s)
+}
+
+// tagSet returns the tags sorted by name.
+func tagSet(tags map[string]string) string {
+	names := make([]string, 0, len(tags))
+	for name := range tags {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	pairs := make([]string, len(names))
+	for i, name := range names {
+		pairs[i] = sanitize(name) + "=" + sanitize(tags[name])
+	}
+
+	return strings.Join(pairs, " ")
+}
+
+// formatValue formats a value and returns whether it can be written at all.
+func formatValue(v interface{}) (string, bool) {
+	switch v := v.(type) {
+	case float64:
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return "", false
+		}
+
+		return strconv.FormatFloat(v, 'f', -1, 64), true
+	case bool:
+		if v {
+			return "1", true
+		}
+
+		return "0", true
+	case int, int64:
+		return fmt.Sprint(v), true
+	}
+
+	return "", false
+}
@@ -0,0 +1,42 @@
+package opentsdbout
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	fake "github.com/powerpu/go-fake-ts"
+)
+
+func ExampleEncoder() {
+	ft, _ := fake.NewTime("ts", time.Date(2020, 2, 7, 0, 0, 0, 0, time.UTC), 1500, 0, 0, false)
+	fr, _ := fake.NewRandom("cpuGate", 4, 0.7, false)
+	cpu, _ := fake.NewDataFromConfig(fake.NewDataConfig("cpu", 8, fake.WithSlope(1)))
+
+	e := NewEncoder(os.Stdout, ft, Series{
+		Metric: "sys.cpu user",
+		Tags:   map[string]string{"host": "host-1", "dc": "eu:west"},
+		Value:  cpu,
+		Gates:  []fake.Gate{fr},
+	})
+	e.Millis = true
+	e.Encode(8)
+
+	// Every metric needs a tag
+	err := NewEncoder(os.Stdout, ft, Series{Metric: "sys.cpu.user", Value: cpu}).Encode(1)
+	fmt.Println(err)
+	// Output:
+	// put sys.cpu_user 1581033600000 50 dc=eu_west host=host-1
+	// put sys.cpu_user 1581033601500 51 dc=eu_west host=host-1
+	// put sys.cpu_user 1581033603000 52 dc=eu_west host=host-1
+	// put sys.cpu_user 1581033606000 54 dc=eu_west host=host-1
+	// put sys.cpu_user 1581033607500 55 dc=eu_west host=host-1
+	// put sys.cpu_user 1581033610500 57 dc=eu_west host=host-1
+	// OpenTSDB metric 'sys.cpu.user' needs at least one tag
+}
+
+func ExampleEncoder_zero() {
+	var e Encoder
+	fmt.Println(e.Encode(1))
+	// Output: opentsdbout: an Encoder must be created with NewEncoder
+}
@@ -0,0 +1,149 @@
+package fake
+
+import (
+	"context"
+	"time"
+)
+
+// Clock tells the time and waits for it to pass. Inject a fake Clock to test
+// a Pacer without sleeping.
+type Clock interface {
+	// Now returns the current time
+	Now() time.Time
+
+	// After waits for the duration to elapse and then sends the current time
+	After(d time.Duration) <-chan time.Time
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time                         { return time.Now() }
+func (systemClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+// SystemClock is the wall clock.
+var SystemClock Clock = systemClock{}
+
+// CatchUp decides what a Pacer does with rows that are late because the
+// consumer (or the machine) could not keep up.
+type CatchUp int
+
+const (
+	// CatchUpBurst emits late rows straight away, one after the other, until
+	// the pacer is back on schedule.
+	CatchUpBurst CatchUp = iota
+
+	// CatchUpSkip drops a late row when the row after it is already due so
+	// the pacer gets back on schedule as soon as possible.
+	CatchUpSkip
+
+	// CatchUpShift emits a late row straight away and moves the schedule of
+	// all following rows back by how late it was, keeping their spacing.
+	CatchUpShift
+)
+
+// PacedRow is a single row emitted by a Pacer.
+type PacedRow struct {
+	// Position of the row, starting at 0. Skipped rows are counted too.
+	Index int64
+
+	// The generated time of the row.
+	Time time.Time
+
+	// The value of every Value of the pacer, in the same order.
+	Values []interface{}
+
+	// How late the row was emitted compared to its schedule.
+	Late time.Duration
+}
+
+// Pacer emits rows in real time: every row is emitted when the clock reaches
+// its generated timestamp, optionally sped up. The first row is emitted
+// straight away and sets the schedule for the rest.
+type Pacer struct {
+	// The time of every row.
+	Time *Time
+
+	// The values of every row. They are advanced in lock-step with Time.
+	Values []Value
+
+	// How much faster than real time rows are emitted, e.g. 60 emits an hour
+	// of rows every minute. Defaults to 1.
+	Speed float64
+
+	// What to do with late rows. Defaults to CatchUpBurst.
+	CatchUp CatchUp
+
+	// How many rows to emit (including skipped ones). 0 means no limit.
+	Limit int64
+
+	// The clock to pace against. Defaults to SystemClock.
+	Clock Clock
+}
+
+// Run emits rows until the context is done, the limit is reached or emit
+// returns an error. It returns the error of emit or the context, if any.
+func (p *Pacer) Run(ctx context.Context, emit func(PacedRow) error) error {
+	clock := p.Clock
+	if clock == nil {
+		clock = SystemClock
+	}
+
+	speed := p.Speed
+	if speed <= 0 {
+		speed = 1
+	}
+
+	wallStart := clock.Now()
+	genStart := p.Time.Time()
+	due := func(ts time.Time) time.Time {
+		return wallStart.Add(time.Duration(float64(ts.Sub(genStart)) / speed))
+	}
+
+	for i := int64(0); p.Limit <= 0 || i < p.Limit; i++ {
+		row := PacedRow{Index: i, Time: p.Time.Time(), Values: make([]interface{}, len(p.Values))}
+		for j, v := range p.Values {
+			row.Values[j] = v.Val()
+		}
+
+		p.Time.Next()
+		for _, v := range p.Values {
+			v.Next()
+		}
+
+		at := due(row.Time)
+		if wait := at.Sub(clock.Now()); wait > 0 {
+			select {
+			case <-clock.After(wait):
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		now := clock.Now()
+		row.Late = now.Sub(at)
+		if row.Late < 0 {
+			row.Late = 0
+		}
+
+		if row.Late > 0 {
+			switch p.CatchUp {
+			case CatchUpSkip:
+				if !due(p.Time.Time()).After(now) {
+					continue
+				}
+			case CatchUpShift:
+				wallStart = wallStart.Add(row.Late)
+			}
+		}
+
+		if err := emit(row); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,63 @@
+package fake
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// fakeClock never sleeps: waiting simply moves the clock forward.
+type fakeClock struct {
+	now time.Time
+}
+
+func (fc *fakeClock) Now() time.Time { return fc.now }
+
+func (fc *fakeClock) After(d time.Duration) <-chan time.Time {
+	fc.now = fc.now.Add(d)
+	ch := make(chan time.Time, 1)
+	ch <- fc.now
+	return ch
+}
+
+func ExamplePacer() {
+	ft, _ := NewTime("pacerTime", time.Date(2020, 2, 7, 0, 0, 0, 0, time.UTC), 60000, 0, 0, false)
+	fp, _ := NewPattern("pacerPattern", 2, 1, false)
+	clock := &fakeClock{now: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)}
+
+	// A minute of generated time passes every second
+	p := &Pacer{Time: ft, Values: []Value{fp}, Speed: 60, Limit: 4, Clock: clock}
+	p.Run(context.Background(), func(row PacedRow) error {
+		fmt.Println(clock.Now().Format("15:04:05"), row.Time.Format("15:04"), row.Values[0])
+		return nil
+	})
+	// Output:
+	// 00:00:00 00:00 true
+	// 00:00:01 00:01 true
+	// 00:00:02 00:02 false
+	// 00:00:03 00:03 true
+}
+
+func ExamplePacer_catchUp() {
+	for _, catchUp := range []CatchUp{CatchUpBurst, CatchUpSkip, CatchUpShift} {
+		ft, _ := NewTime("pacerTime", time.Date(2020, 2, 7, 0, 0, 0, 0, time.UTC), 1000, 0, 0, false)
+		clock := &fakeClock{now: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)}
+
+		// The consumer is stuck for 2.5 seconds on the second row
+		p := &Pacer{Time: ft, CatchUp: catchUp, Limit: 6, Clock: clock}
+		var emitted []string
+		p.Run(context.Background(), func(row PacedRow) error {
+			emitted = append(emitted, fmt.Sprintf("%v@%v", row.Index, clock.Now().Format("05.0")))
+			if row.Index == 1 {
+				clock.now = clock.now.Add(2500 * time.Millisecond)
+			}
+			return nil
+		})
+		fmt.Println(strings.Join(emitted, " "))
+	}
+	// Output:
+	// 0@00.0 1@01.0 2@03.5 3@03.5 4@04.0 5@05.0
+	// 0@00.0 1@01.0 3@03.5 4@04.0 5@05.0
+	// 0@00.0 1@01.0 2@03.5 3@04.5 4@05.5 5@06.5
+}
@@ -3,19 +3,28 @@ package fake
 import (
 	"encoding/json"
 	"errors"
-	"math"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
 )
 
-// Pattern generates true/false values based on a predetermined pattern.
+// Pattern generates true/false values based on a predetermined pattern. The
+// pattern is a cycle of runs of good and bad samples that repeats forever, or
+// daily time windows of bad samples (see NewTimePattern).
 type Pattern struct {
-	id           string
-	i            int64
-	patternGood  int
-	patternBad   int
-	patternRatio float64
-	keepStats    bool
-	Stats        *PatternStats
-	v            bool
+	id        string
+	i         int64
+	runs      []patternRun
+	ends      []int64
+	cycle     int64
+	phase     int64
+	windows   []patternWindow
+	start     time.Time
+	increment time.Duration
+	keepStats bool
+	Stats     *PatternStats
+	v         bool
 }
 
 // PatternStats keeps track of various statistics of a Pattern while it's running.
@@ -81,13 +90,43 @@ func (ps *PatternStats) JSON() string {
 // Next generates the next pattern value.
 func (fp *Pattern) Next() {
 	fp.i = fp.i + 1
-	f := math.Floor((float64(fp.i)/float64(fp.patternGood+fp.patternBad))*100000000) / 100000000
-	fp.v = (fp.patternBad == 0 || ((f-math.Trunc(f)) <= fp.patternRatio && (f-math.Trunc(f)) != 0))
+	fp.v = fp.value(fp.i)
 	if fp.keepStats {
 		fp.Stats.Add(fp.v)
 	}
 }
 
+// value calculates the pattern value for the internal counter i, the first
+// sample being 1.
+func (fp *Pattern) value(i int64) bool {
+	if fp.windows != nil {
+		return !inWindows(fp.windows, fp.start.Add(time.Duration(i-1+fp.phase)*fp.increment))
+	}
+
+	// Integers only so that the position never drifts, however long it runs
+	pos := (i - 1 + fp.phase) % fp.cycle
+	k := sort.Search(len(fp.ends), func(k int) bool { return fp.ends[k] > pos })
+	return fp.runs[k].good
+}
+
+// Index returns the position of the current value, the first sample being 0.
+func (fp *Pattern) Index() int64 {
+	return fp.i - 1
+}
+
+// SeekTo jumps to sample n (the first sample being 0) without generating the
+// samples in between. Only the sample jumped to is added to the statistics.
+func (fp *Pattern) SeekTo(n int64) {
+	fp.i = n
+	fp.Next()
+}
+
+// At returns the value of sample n (the first sample being 0) without
+// changing the current value.
+func (fp *Pattern) At(n int64) bool {
+	return fp.value(n + 1)
+}
+
 // Val returns the current pattern value.
 func (fp *Pattern) Val() interface{} {
 	return fp.v
@@ -103,6 +142,44 @@ func (fp *Pattern) JSONStats() string {
 	return fp.Stats.JSON()
 }
 
+// ID returns the unique id.
+func (fp *Pattern) ID() string {
+	return fp.id
+}
+
+// patternState is the runtime state of a Pattern saved by Snapshot().
+type patternState struct {
+	ID    string       `json:"id"`
+	I     int64        `json:"i"`
+	V     bool         `json:"v"`
+	Stats PatternStats `json:"stats"`
+}
+
+// Snapshot saves the runtime state of the pattern, including its statistics,
+// so it can be restored later with Restore().
+func (fp *Pattern) Snapshot() ([]byte, error) {
+	return json.Marshal(patternState{ID: fp.id, I: fp.i, V: fp.v, Stats: *fp.Stats})
+}
+
+// Restore restores the runtime state saved by Snapshot(). The pattern must
+// have been created with the same parameters as the one that was saved and
+// will continue with the identical sequence.
+func (fp *Pattern) Restore(b []byte) error {
+	var st patternState
+	if err := json.Unmarshal(b, &st); err != nil {
+		return err
+	}
+
+	if st.ID != fp.id {
+		return errors.New("cannot restore a fake pattern with id '" + fp.id + "' from a snapshot of id '" + st.ID + "'")
+	}
+
+	fp.i = st.I
+	fp.v = st.V
+	*fp.Stats = st.Stats
+	return nil
+}
+
 // Good returns whether the current value is "good".
 func (fp *Pattern) Good() bool {
 	return fp.v
@@ -116,18 +193,36 @@ func (fp *Pattern) Bad() bool {
 // Values returns the next count of values as a bool array.
 func (fp *Pattern) Values(count int) []bool {
 	out := make([]bool, count)
+	fp.Fill(out)
+	return out
+}
 
-	for i := 0; i < count; i++ {
-		out[i] = fp.Good()
+// Step returns the current value and generates the next one.
+func (fp *Pattern) Step() bool {
+	out := fp.Good()
+	fp.Next()
+	return out
+}
+
+// Fill writes the next len(dst) values into dst without allocating and
+// returns how many were written.
+func (fp *Pattern) Fill(dst []bool) int {
+	for i := range dst {
+		dst[i] = fp.Good()
 		fp.Next()
 	}
 
-	return out
+	return len(dst)
 }
 
 // NewPattern creates a new pattern. A pattern has a unique id, number of
 // required "good" samples followed by a number of required "bad" samples and
 // needs to know wheter to keep internal statistics.
+//
+// The values repeat exactly every good+bad samples. Older versions worked
+// out the position in the cycle with floating point and drifted for some
+// combinations, e.g. 1 good and 4 bad samples was bad from sample 11 on, so
+// such patterns generate different (correct) values than before.
 func NewPattern(id string, good int, bad int, keepStats bool) (*Pattern, error) {
 	if id == "" {
 		return nil, errors.New("ID for a fake pattern cannot be blank")
@@ -141,15 +236,361 @@ func NewPattern(id string, good int, bad int, keepStats bool) (*Pattern, error)
 		return nil, errors.New("good and bad in a fake pattern with id '" + id + "' cannot both be 0")
 	}
 
+	return newPattern(id, blocks(good, bad), 0, keepStats), nil
+}
+
+// PatternConfig holds every parameter of a Pattern. The cycle is either Good
+// samples followed by Bad samples (see NewPattern) or a Sequence.
+type PatternConfig struct {
+	ID   string `json:"id" yaml:"id"`
+	Good int    `json:"good" yaml:"good"`
+	Bad  int    `json:"bad" yaml:"bad"`
+
+	// The cycle as a sequence of good ("G") and bad ("B") samples, each
+	// optionally repeated with "*n". Parentheses group samples to repeat them
+	// together, e.g. "(G*23 B)*6 G*20 B*4" is a day of hourly samples with a
+	// bad one every 24 hours plus a longer outage at the end of the day.
+	// Overrides Good and Bad.
+	Sequence string `json:"sequence" yaml:"sequence"`
+
+	// How many samples into the cycle the pattern starts, e.g. 20 starts the
+	// sequence "G*23 B" 3 samples before the bad one.
+	Phase int64 `json:"phase" yaml:"phase"`
+
+	KeepStats bool `json:"keepStats" yaml:"keepStats"`
+}
+
+// Validate checks the config for values that would generate nonsense. It
+// returns nil or a *ConfigError listing every invalid field.
+func (cfg PatternConfig) Validate() error {
+	ce := &ConfigError{Kind: "pattern", ID: cfg.ID}
+
+	if cfg.ID == "" {
+		ce.add("ID", cfg.ID, "cannot be blank")
+	}
+
+	if cfg.Sequence != "" {
+		if _, err := parseSequence(cfg.Sequence); err != nil {
+			ce.add("Sequence", cfg.Sequence, err.Error())
+		}
+	} else {
+		if cfg.Good < 0 {
+			ce.add("Good", cfg.Good, "cannot be less than 0")
+		}
+
+		if cfg.Bad < 0 {
+			ce.add("Bad", cfg.Bad, "cannot be less than 0")
+		}
+
+		if cfg.Good == 0 && cfg.Bad == 0 {
+			ce.add("Good", cfg.Good, "and Bad cannot both be 0")
+		}
+	}
+
+	if cfg.Phase < 0 {
+		ce.add("Phase", cfg.Phase, "cannot be less than 0")
+	}
+
+	return ce.err()
+}
+
+// NewPatternFromConfig creates a new pattern from a PatternConfig. The config
+// is validated first and a *ConfigError listing every invalid field is
+// returned if it is not valid.
+func NewPatternFromConfig(cfg PatternConfig) (*Pattern, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	runs := blocks(cfg.Good, cfg.Bad)
+	if cfg.Sequence != "" {
+		runs, _ = parseSequence(cfg.Sequence)
+	}
+
+	return newPattern(cfg.ID, runs, cfg.Phase, cfg.KeepStats), nil
+}
+
+// NewTimePattern creates a new pattern that is bad during daily time windows,
+// e.g. "02:00-02:15" for a nightly backup, and good otherwise. Windows are
+// "HH:MM-HH:MM" or "HH:MM:SS-HH:MM:SS" in the time zone of the start of the
+// Time, include their start but not their end and may span midnight (e.g.
+// "23:50-00:10").
+//
+// The pattern follows the scheduled timestamps of the Time, i.e. its start
+// plus an increment for every sample before the variance is applied. It does
+// not advance the Time.
+func NewTimePattern(id string, ft *Time, windows []string, keepStats bool) (*Pattern, error) {
+	if id == "" {
+		return nil, errors.New("ID for a fake pattern cannot be blank")
+	}
+
+	if ft == nil {
+		return nil, errors.New("time of a fake pattern with id '" + id + "' cannot be nil")
+	}
+
+	pws, err := parseWindows(windows)
+	if err != nil {
+		return nil, errors.New("windows of a fake pattern with id '" + id + "' " + err.Error())
+	}
+
 	p := &Pattern{
-		id:           id,
-		patternGood:  good,
-		patternBad:   bad,
-		patternRatio: float64(good) / float64(good+bad),
-		keepStats:    keepStats,
-		Stats:        &PatternStats{ID: id},
+		id:        id,
+		windows:   pws,
+		start:     ft.start,
+		increment: time.Duration(ft.increment) * time.Millisecond,
+		keepStats: keepStats,
+		Stats:     &PatternStats{ID: id},
 	}
 
 	p.Next()
 	return p, nil
 }
+
+// newPattern creates a pattern repeating the runs.
+func newPattern(id string, runs []patternRun, phase int64, keepStats bool) *Pattern {
+	p := &Pattern{
+		id:        id,
+		runs:      runs,
+		ends:      make([]int64, len(runs)),
+		keepStats: keepStats,
+		Stats:     &PatternStats{ID: id},
+	}
+
+	for i, r := range runs {
+		p.cycle += r.n
+		p.ends[i] = p.cycle
+	}
+	p.phase = phase % p.cycle
+
+	p.Next()
+	return p
+}
+
+// patternRun is a run of n good or bad samples of a pattern cycle.
+type patternRun struct {
+	good bool
+	n    int64
+}
+
+// blocks returns the runs of good samples followed by bad samples.
+func blocks(good int, bad int) []patternRun {
+	return appendRuns(appendRuns(nil, patternRun{true, int64(good)}), patternRun{false, int64(bad)})
+}
+
+// appendRuns appends runs, merging neighbours of the same value and dropping
+// empty ones.
+func appendRuns(runs []patternRun, more ...patternRun) []patternRun {
+	for _, r := range more {
+		switch {
+		case r.n == 0:
+		case len(runs) > 0 && runs[len(runs)-1].good == r.good:
+			runs[len(runs)-1].n += r.n
+		default:
+			runs = append(runs, r)
+		}
+	}
+
+	return runs
+}
+
+// The limits of a parsed sequence, to fail on typos rather than run out of
+// memory
+const (
+	maxSequenceRuns  = 1 << 20
+	maxSequenceCycle = 1 << 62
+)
+
+// parseSequence parses a sequence like "(G*23 B)*6 G*20 B*4" into runs.
+func parseSequence(spec string) ([]patternRun, error) {
+	sp := sequenceParser{spec: spec}
+	runs, err := sp.sequence()
+	if err != nil {
+		return nil, err
+	}
+
+	if sp.pos < len(spec) {
+		return nil, sp.errorf("has an unexpected ')'")
+	}
+
+	if len(runs) == 0 {
+		return nil, errors.New("must have at least one sample")
+	}
+
+	return runs, nil
+}
+
+// sequenceParser is a recursive descent parser of pattern sequences:
+//
+//  sequence = { item }
+//  item     = ( "G" | "B" | "(" sequence ")" ) [ "*" count ]
+type sequenceParser struct {
+	spec string
+	pos  int
+}
+
+func (sp *sequenceParser) errorf(format string, args ...interface{}) error {
+	return errors.New(fmt.Sprintf(format, args...) + " at position " + fmt.Sprintf("%v", sp.pos+1))
+}
+
+// skip skips white space.
+func (sp *sequenceParser) skip() {
+	for sp.pos < len(sp.spec) && (sp.spec[sp.pos] == ' ' || sp.spec[sp.pos] == '\t' || sp.spec[sp.pos] == '\n') {
+		sp.pos++
+	}
+}
+
+// sequence parses items until the end or a closing parenthesis.
+func (sp *sequenceParser) sequence() ([]patternRun, error) {
+	var runs []patternRun
+	var cycle int64
+
+	for {
+		sp.skip()
+		if sp.pos >= len(sp.spec) || sp.spec[sp.pos] == ')' {
+			return runs, nil
+		}
+
+		var item []patternRun
+		switch c := sp.spec[sp.pos]; c {
+		case 'G', 'B':
+			sp.pos++
+			item = []patternRun{{c == 'G', 1}}
+		case '(':
+			sp.pos++
+			var err error
+			if item, err = sp.sequence(); err != nil {
+				return nil, err
+			}
+
+			if sp.pos >= len(sp.spec) {
+				return nil, sp.errorf("is missing ')'")
+			}
+			sp.pos++
+
+			if len(item) == 0 {
+				return nil, sp.errorf("has an empty group")
+			}
+		default:
+			return nil, sp.errorf("has an unexpected '%c'", c)
+		}
+
+		count, err := sp.count()
+		if err != nil {
+			return nil, err
+		}
+
+		var n int64
+		for _, r := range item {
+			n += r.n
+		}
+
+		if n > (maxSequenceCycle-cycle)/count {
+			return nil, sp.errorf("has a cycle longer than %v samples", int64(maxSequenceCycle))
+		}
+		cycle += n * count
+
+		if len(item) == 1 {
+			runs = appendRuns(runs, patternRun{item[0].good, item[0].n * count})
+			continue
+		}
+
+		if count > (maxSequenceRuns-int64(len(runs)))/int64(len(item)) {
+			return nil, sp.errorf("has more than %v runs of good and bad samples", maxSequenceRuns)
+		}
+
+		for k := int64(0); k < count; k++ {
+			runs = appendRuns(runs, item...)
+		}
+	}
+}
+
+// count parses an optional "*n" and returns n or 1 without one.
+func (sp *sequenceParser) count() (int64, error) {
+	sp.skip()
+	if sp.pos >= len(sp.spec) || sp.spec[sp.pos] != '*' {
+		return 1, nil
+	}
+	sp.pos++
+	sp.skip()
+
+	start := sp.pos
+	var n int64
+	for sp.pos < len(sp.spec) && sp.spec[sp.pos] >= '0' && sp.spec[sp.pos] <= '9' {
+		if n > (maxSequenceCycle-9)/10 {
+			return 0, sp.errorf("has a count that is too large")
+		}
+		n = n*10 + int64(sp.spec[sp.pos]-'0')
+		sp.pos++
+	}
+
+	if sp.pos == start || n == 0 {
+		return 0, sp.errorf("needs a count greater than 0 after '*'")
+	}
+
+	return n, nil
+}
+
+// patternWindow is a daily time window, as durations since midnight. A
+// window with an end before its start spans midnight.
+type patternWindow struct {
+	from time.Duration
+	to   time.Duration
+}
+
+// parseWindows parses windows like "02:00-02:15".
+func parseWindows(windows []string) ([]patternWindow, error) {
+	if len(windows) == 0 {
+		return nil, errors.New("must have at least one window")
+	}
+
+	out := make([]patternWindow, len(windows))
+	for i, w := range windows {
+		from, to, ok := strings.Cut(w, "-")
+		f, err1 := timeOfDay(from)
+		t, err2 := timeOfDay(to)
+		if !ok || err1 != nil || err2 != nil {
+			return nil, errors.New("must be 'HH:MM-HH:MM' but was '" + w + "'")
+		}
+
+		if f == t {
+			return nil, errors.New("cannot start and end at the same time but was '" + w + "'")
+		}
+
+		out[i] = patternWindow{f, t}
+	}
+
+	return out, nil
+}
+
+// timeOfDay parses "HH:MM" or "HH:MM:SS" into a duration since midnight.
+func timeOfDay(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+
+	t, err := time.Parse("15:04:05", s)
+	if err != nil {
+		if t, err = time.Parse("15:04", s); err != nil {
+			return 0, err
+		}
+	}
+
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute + time.Duration(t.Second())*time.Second, nil
+}
+
+// inWindows returns whether the time of day of ts falls into a window.
+func inWindows(windows []patternWindow, ts time.Time) bool {
+	h, m, sec := ts.Clock()
+	d := time.Duration(h)*time.Hour + time.Duration(m)*time.Minute + time.Duration(sec)*time.Second + time.Duration(ts.Nanosecond())
+
+	for _, w := range windows {
+		if w.from < w.to && d >= w.from && d < w.to {
+			return true
+		}
+
+		// Spanning midnight
+		if w.from > w.to && (d >= w.from || d < w.to) {
+			return true
+		}
+	}
+
+	return false
+}
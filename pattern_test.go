@@ -2,6 +2,8 @@ package fake
 
 import (
 	"fmt"
+	"strings"
+	"time"
 )
 
 func ExampleNewPattern() {
@@ -19,3 +21,94 @@ func ExampleNewPattern_b() {
 	}
 	// Output: true true true true true true true true true true
 }
+
+func ExamplePattern_Snapshot() {
+	fp1, _ := NewPattern("fakePattern3", 2, 1, true)
+	fp1.Vals(4)
+	snapshot, _ := fp1.Snapshot()
+
+	fp2, _ := NewPattern("fakePattern3", 2, 1, true)
+	fp2.Restore(snapshot)
+	fmt.Printf("%v\n", fp2.Vals(6))
+	// Output: [true false true true false true]
+}
+
+func ExamplePattern_At() {
+	fp, _ := NewPattern("fakePattern4", 23, 1, false)
+	fmt.Println(fp.At(22), fp.At(23), fp.At(24*1000000+23))
+	// Output: true false false
+}
+
+// Positions are tracked with integers so very long runs never drift.
+func ExamplePattern_At_long() {
+	fp, _ := NewPattern("fakePattern5", 3, 1, false)
+	n := int64(4_000_000_000_000_000)
+	fmt.Println(fp.At(n), fp.At(n+1), fp.At(n+2), fp.At(n+3))
+	// Output:
+	// true true true false
+}
+
+// Until positions were tracked with integers some patterns drifted after a
+// few cycles: 1 good and 4 bad samples stayed bad from sample 11 on.
+func ExampleNewPattern_drift() {
+	fp, _ := NewPattern("fakePattern7", 1, 4, false)
+	fmt.Println(fp.Vals(20))
+	// Output:
+	// [true false false false false true false false false false true false false false false true false false false false]
+}
+
+func ExampleNewPatternFromConfig() {
+	fp, _ := NewPatternFromConfig(PatternConfig{ID: "fakePattern6", Sequence: "G*3 (B G)*2 B*3"})
+
+	var sb strings.Builder
+	for _, good := range fp.Values(20) {
+		if good {
+			sb.WriteString("G")
+		} else {
+			sb.WriteString("B")
+		}
+	}
+	fmt.Println(sb.String())
+	// Output:
+	// GGGBGBGBBBGGGBGBGBBB
+}
+
+func ExampleNewPatternFromConfig_phase() {
+	// Hourly samples that are bad once a day, starting 3 hours before it
+	fp, _ := NewPatternFromConfig(PatternConfig{ID: "fakePattern7", Sequence: "G*23 B", Phase: 20})
+	fmt.Println(fp.Values(6))
+	// Output:
+	// [true true true false true true]
+}
+
+func ExamplePatternConfig_Validate() {
+	for _, seq := range []string{"G*23 B*", "(G B", "G B)", "G*0", "()", "G x"} {
+		fmt.Println(PatternConfig{ID: "fakePattern8", Sequence: seq}.Validate())
+	}
+	// Output:
+	// invalid fake pattern with id 'fakePattern8': Sequence needs a count greater than 0 after '*' at position 8 but was 'G*23 B*'
+	// invalid fake pattern with id 'fakePattern8': Sequence is missing ')' at position 5 but was '(G B'
+	// invalid fake pattern with id 'fakePattern8': Sequence has an unexpected ')' at position 4 but was 'G B)'
+	// invalid fake pattern with id 'fakePattern8': Sequence needs a count greater than 0 after '*' at position 4 but was 'G*0'
+	// invalid fake pattern with id 'fakePattern8': Sequence has an empty group at position 3 but was '()'
+	// invalid fake pattern with id 'fakePattern8': Sequence has an unexpected 'x' at position 3 but was 'G x'
+}
+
+func ExampleNewTimePattern() {
+	// A nightly backup every day from 02:00 to 02:15
+	ft, _ := NewTime("fakeTime", time.Date(2020, 2, 7, 1, 50, 0, 0, time.UTC), 5*60*1000, 0, 0, false)
+	fp, _ := NewTimePattern("backup", ft, []string{"02:00-02:15"}, false)
+
+	for i := 0; i < 6; i++ {
+		fmt.Println(ft.Time().Format("15:04"), fp.Good())
+		ft.Next()
+		fp.Next()
+	}
+	// Output:
+	// 01:50 true
+	// 01:55 true
+	// 02:00 false
+	// 02:05 false
+	// 02:10 false
+	// 02:15 true
+}
@@ -0,0 +1,105 @@
+package playground
+
+import (
+	"strconv"
+	"strings"
+
+	fake "github.com/powerpu/go-fake-ts"
+)
+
+// GoSource returns Go code creating the config with NewDataConfig and the
+// With options, leaving out options that only repeat the defaults.
+func GoSource(cfg fake.DataConfig) string {
+	def := fake.NewDataConfig(cfg.ID, cfg.Samples)
+
+	var opts []string
+	add := func(name string, args ...string) {
+		opts = append(opts, "fake."+name+"("+strings.Join(args, ", ")+")")
+	}
+
+	if cfg.From != def.From || cfg.To != def.To {
+		add("WithRange", float(cfg.From), float(cfg.To))
+	}
+
+	if cfg.LimitLower || cfg.LimitUpper {
+		add("WithLimits", strconv.FormatBool(cfg.LimitLower), strconv.FormatBool(cfg.LimitUpper))
+	}
+
+	if cfg.StretchStart != def.StretchStart || cfg.StretchEnd != def.StretchEnd {
+		add("WithStretch", float(cfg.StretchStart), float(cfg.StretchEnd))
+	}
+
+	if cfg.Slope != 0 {
+		add("WithSlope", float(cfg.Slope))
+	}
+
+	if cfg.Bump != 0 {
+		add("WithBump", float(cfg.Bump))
+	}
+
+	if cfg.PermaBumpAt != 0 || cfg.PermaBumpBy != 0 || cfg.PermaBumpSmoother != 0 {
+		add("WithPermaBump", integer(cfg.PermaBumpAt), float(cfg.PermaBumpBy), integer(cfg.PermaBumpSmoother))
+	}
+
+	if cfg.UseRandom {
+		add("WithRandom", integer(cfg.Seed), float(cfg.Bias))
+	}
+
+	if cfg.CounterRandom {
+		add("WithCounterRandom")
+	}
+
+	if cfg.Spike {
+		add("WithSpike", integer(cfg.SpikeEvery), integer(cfg.SpikeSustain), integer(cfg.SpikeTo), integer(cfg.SpikeSmoother))
+	}
+
+	if cfg.SpikeWobble {
+		add("WithSpikeWobble", integer(cfg.SpikeWobbleFactor))
+	}
+
+	if cfg.Seasonality {
+		waves := []int64{cfg.SeasonalityWave1, cfg.SeasonalityWave2, cfg.SeasonalityWave3, cfg.SeasonalityWave4, cfg.SeasonalityWave5}
+
+		// Trailing waves of 1 are the default
+		n := len(waves)
+		for n > 0 && waves[n-1] == 1 {
+			n--
+		}
+
+		args := make([]string, n)
+		for i := range args {
+			args[i] = integer(waves[i])
+		}
+		add("WithSeasonality", args...)
+	}
+
+	if cfg.KeepStats {
+		add("WithStats")
+	}
+
+	var sb strings.Builder
+	sb.WriteString("cfg := fake.NewDataConfig(" + strconv.Quote(cfg.ID) + ", " + integer(cfg.Samples))
+	if len(opts) > 0 {
+		sb.WriteString(",\n")
+		for _, opt := range opts {
+			sb.WriteString("\t" + opt + ",\n")
+		}
+	}
+	sb.WriteString(")\n")
+
+	// Without an option the seed only matters when set directly
+	if !cfg.UseRandom && cfg.Seed != def.Seed {
+		sb.WriteString("cfg.Seed = " + integer(cfg.Seed) + "\n")
+	}
+
+	sb.WriteString("fd, err := fake.NewDataFromConfig(cfg)\n")
+	return sb.String()
+}
+
+func float(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+func integer(v int64) string {
+	return strconv.FormatInt(v, 10)
+}
@@ -0,0 +1,187 @@
+// Package playground serves a web page to design a Data series
+// interactively, replacing the spreadsheet the generator was modelled on.
+//
+// The page has a slider for every parameter of NewData. Every change
+// regenerates the series on the server with the real generator and plots it
+// in the browser, shading spikes and showing the statistics of the series.
+// Once it looks right, "Export" shows the matching DataConfig as JSON (for a
+// scenario file) and as Go:
+//
+//  s := playground.NewServer(fake.NewDataConfig("cpu", 1440, fake.WithRandom(1, 0.5)))
+//  err := http.ListenAndServe("localhost:8080", s)
+//
+// The page itself is embedded so the playground works offline (also
+// "fakets playground").
+package playground
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+
+	fake "github.com/powerpu/go-fake-ts"
+)
+
+//go:embed index.html
+var index []byte
+
+// Server serves the playground page and regenerates series for it:
+//
+//  GET  /         the page
+//  GET  /config   the initial DataConfig as JSON
+//  POST /data     generates the DataConfig in the body, see Result
+type Server struct {
+	// The config the page starts with.
+	Config fake.DataConfig
+
+	// The most samples generated per request, to keep the page responsive.
+	// Defaults to 10000.
+	MaxSamples int64
+}
+
+// NewServer creates a Server starting from the given config.
+func NewServer(cfg fake.DataConfig) *Server {
+	return &Server{Config: cfg, MaxSamples: 10000}
+}
+
+// Result is the response of POST /data.
+type Result struct {
+	// Every generated value.
+	Values []float64 `json:"values"`
+
+	// Whether each value is part of a spike.
+	Spiking []bool `json:"spiking"`
+
+	// The statistics of all values.
+	Stats *fake.DataStats `json:"stats"`
+
+	// The config as it would go into a scenario file.
+	JSON string `json:"json"`
+
+	// The config as Go code, see GoSource.
+	Go string `json:"go"`
+}
+
+// Failure is the response of POST /data when the config is invalid.
+type Failure struct {
+	// What went wrong.
+	Error string `json:"error"`
+
+	// The reason of every invalid field, by field name (see
+	// fake.ConfigError).
+	Fields map[string]string `json:"fields,omitempty"`
+}
+
+// ServeHTTP serves the page, the initial config and generated series.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case "/":
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write(index)
+	case "/config":
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		reply(w, http.StatusOK, s.Config)
+	case "/data":
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		// Missing fields keep their defaults
+		cfg := fake.NewDataConfig("", 0)
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<16)).Decode(&cfg); err != nil {
+			reply(w, http.StatusBadRequest, Failure{Error: "invalid config: " + err.Error()})
+			return
+		}
+
+		res, err := s.generate(cfg)
+		if err != nil {
+			f := Failure{Error: err.Error()}
+
+			var ce *fake.ConfigError
+			if errors.As(err, &ce) {
+				f.Fields = make(map[string]string, len(ce.Fields))
+				for _, fe := range ce.Fields {
+					f.Fields[fe.Field] = fe.Reason
+				}
+			}
+
+			reply(w, http.StatusUnprocessableEntity, f)
+			return
+		}
+
+		reply(w, http.StatusOK, res)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// generate generates every sample of the config.
+func (s *Server) generate(cfg fake.DataConfig) (*Result, error) {
+	max := s.MaxSamples
+	if max <= 0 {
+		max = 10000
+	}
+
+	if cfg.Samples > max {
+		return nil, errors.New("the playground generates at most " + strconv.FormatInt(max, 10) + " samples")
+	}
+
+	fd, err := fake.NewDataFromConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &Result{
+		Values:  make([]float64, cfg.Samples),
+		Spiking: make([]bool, cfg.Samples),
+		Stats:   fake.NewDataStats(cfg.ID, cfg.From, cfg.To),
+		Go:      GoSource(cfg),
+	}
+	res.Stats.Seed = cfg.Seed
+
+	for i := range res.Values {
+		v := fd.Float()
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return nil, errors.New("sample " + strconv.Itoa(i) + " is not a number, check the stretch")
+		}
+
+		res.Values[i] = v
+		res.Spiking[i] = fd.Spiking()
+		res.Stats.Add(v)
+		fd.Next()
+	}
+
+	res.Stats.JSON() // Calculates the slopes
+
+	out, _ := json.MarshalIndent(cfg, "", "  ")
+	res.JSON = string(out)
+
+	return res, nil
+}
+
+// reply writes v as JSON. It is encoded before the status is written so a
+// value that cannot be encoded is a 500 and not a 200 with an empty body.
+func reply(w http.ResponseWriter, status int, v interface{}) {
+	out, err := json.Marshal(v)
+	if err != nil {
+		status = http.StatusInternalServerError
+		out, _ = json.Marshal(Failure{Error: "cannot encode the response: " + err.Error()})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(append(out, '\n'))
+}
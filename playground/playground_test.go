@@ -0,0 +1,99 @@
+package playground
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	fake "github.com/powerpu/go-fake-ts"
+)
+
+func ExampleServer() {
+	srv := httptest.NewServer(NewServer(fake.NewDataConfig("cpu", 1000)))
+	defer srv.Close()
+
+	// What the page sends when a slider moves
+	cfg := fake.NewDataConfig("cpu", 8, fake.WithSlope(2), fake.WithSpike(4, 1, 90, 1))
+	body, _ := json.Marshal(cfg)
+
+	resp, err := http.Post(srv.URL+"/data", "application/json", strings.NewReader(string(body)))
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	defer resp.Body.Close()
+
+	var res Result
+	json.NewDecoder(resp.Body).Decode(&res)
+	fmt.Println(resp.StatusCode, res.Values, res.Spiking)
+	fmt.Println(res.Stats.CMin, res.Stats.CMax)
+	fmt.Print(res.Go)
+	// Output:
+	// 200 [50 52 54 56 90 90 62 90] [false false false true true true true true]
+	// 50 90
+	// cfg := fake.NewDataConfig("cpu", 8,
+	// 	fake.WithSlope(2),
+	// 	fake.WithSpike(4, 1, 90, 1),
+	// )
+	// fd, err := fake.NewDataFromConfig(cfg)
+}
+
+// A single sample has no slope yet.
+func ExampleServer_oneSample() {
+	srv := httptest.NewServer(NewServer(fake.NewDataConfig("cpu", 1000)))
+	defer srv.Close()
+
+	resp, err := http.Post(srv.URL+"/data", "application/json", strings.NewReader(`{"id": "cpu", "samples": 1}`))
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	defer resp.Body.Close()
+
+	var raw struct {
+		Values []float64                  `json:"values"`
+		Stats  map[string]json.RawMessage `json:"stats"`
+	}
+	json.NewDecoder(resp.Body).Decode(&raw)
+	fmt.Println(resp.StatusCode, raw.Values, string(raw.Stats["cumulativeSlope"]), string(raw.Stats["slotSlope"]))
+	// Output:
+	// 200 [50] null 0
+}
+
+func ExampleServer_invalid() {
+	srv := httptest.NewServer(NewServer(fake.NewDataConfig("cpu", 1000)))
+	defer srv.Close()
+
+	resp, err := http.Post(srv.URL+"/data", "application/json", strings.NewReader(`{"id": "cpu", "samples": 10, "from": 10, "to": 0}`))
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	defer resp.Body.Close()
+
+	out, _ := io.ReadAll(resp.Body)
+	fmt.Println(resp.StatusCode)
+	fmt.Print(string(out))
+	// Output:
+	// 422
+	// {"error":"invalid fake data with id 'cpu': From cannot be greater than To (0) but was '10'","fields":{"From":"cannot be greater than To (0)"}}
+}
+
+func ExampleGoSource() {
+	cfg := fake.NewDataConfig("cpu", 1440,
+		fake.WithRange(0, 50),
+		fake.WithRandom(1, 0.5),
+		fake.WithSeasonality(1440, 60))
+
+	fmt.Print(GoSource(cfg))
+	// Output:
+	// cfg := fake.NewDataConfig("cpu", 1440,
+	// 	fake.WithRange(0, 50),
+	// 	fake.WithRandom(1, 0.5),
+	// 	fake.WithSeasonality(1440, 60),
+	// )
+	// fd, err := fake.NewDataFromConfig(cfg)
+}
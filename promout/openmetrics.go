@@ -0,0 +1,130 @@
+package promout
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	fake "github.com/powerpu/go-fake-ts"
+)
+
+// OpenMetricsEncoder writes a Time and a set of metrics in the OpenMetrics
+// text format, e.g. to backfill a TSDB. Every call to Encode advances the
+// Time, the gates and all metrics in lock-step so none of them may appear
+// twice or be used elsewhere while encoding.
+//
+// An OpenMetricsEncoder must be created with NewOpenMetricsEncoder. The zero
+// value has nowhere to write to.
+type OpenMetricsEncoder struct {
+	// The timestamp of every sample.
+	Time *fake.Time
+
+	// Decide whether a sample is "good" for all metrics. Optional.
+	Gates []fake.Gate
+
+	// The metrics to encode. A sample whose gate is "bad" is left out. The
+	// samples of counters are named "<family>_total", a name ending in
+	// "_total" is used as is.
+	Metrics []Metric
+
+	w io.Writer
+	s sampler
+}
+
+// NewOpenMetricsEncoder creates an OpenMetricsEncoder writing to w.
+func NewOpenMetricsEncoder(w io.Writer, ft *fake.Time, metrics ...Metric) *OpenMetricsEncoder {
+	return &OpenMetricsEncoder{Time: ft, Metrics: metrics, w: w}
+}
+
+// Encode writes the next count samples of every metric as a complete
+// exposition ending in "# EOF".
+func (e *OpenMetricsEncoder) Encode(count int) error {
+	if e.w == nil {
+		return errors.New("promout: an OpenMetricsEncoder must be created with NewOpenMetricsEncoder")
+	}
+
+	points := e.s.sample(e.Time, e.Gates, e.Metrics, count)
+
+	// Group metrics by family in the order the families first appear
+	var families []string
+	members := map[string][]int{}
+	for i, m := range e.Metrics {
+		family := m.Name
+		if m.Kind == Counter {
+			family = strings.TrimSuffix(family, "_total")
+		}
+
+		if _, ok := members[family]; !ok {
+			families = append(families, family)
+		}
+
+		members[family] = append(members[family], i)
+	}
+
+	var buf bytes.Buffer
+	for _, family := range families {
+		first := e.Metrics[members[family][0]]
+
+		kind := "gauge"
+		if first.Kind == Counter {
+			kind = "counter"
+		}
+		buf.WriteString("# TYPE " + family + " " + kind + "\n")
+
+		for _, i := range members[family] {
+			if e.Metrics[i].Help != "" {
+				buf.WriteString("# HELP " + family + " " + helpEscaper.Replace(e.Metrics[i].Help) + "\n")
+				break
+			}
+		}
+
+		for _, i := range members[family] {
+			m := e.Metrics[i]
+
+			name := family
+			if m.Kind == Counter {
+				name += "_total"
+			}
+
+			for _, p := range points[i] {
+				buf.WriteString(name)
+				writeLabels(&buf, m.Labels)
+				buf.WriteString(" " + formatFloat(p.value) + " " + formatSeconds(p.ts))
+
+				// Only counters may carry exemplars
+				if p.exemplar != nil && m.Kind == Counter {
+					buf.WriteString(" #")
+					writeExemplarLabels(&buf, p.exemplar.Labels)
+					buf.WriteString(" " + formatFloat(p.exemplar.Value) + " " + formatSeconds(p.exemplar.Timestamp))
+				}
+
+				buf.WriteByte('\n')
+			}
+		}
+	}
+
+	buf.WriteString("# EOF\n")
+
+	_, err := e.w.Write(buf.Bytes())
+	return err
+}
+
+// writeExemplarLabels writes the labels of an exemplar, which are written
+// even if there are none.
+func writeExemplarLabels(buf *bytes.Buffer, labels map[string]string) {
+	if len(labels) == 0 {
+		buf.WriteString(" {}")
+		return
+	}
+
+	buf.WriteByte(' ')
+	writeLabels(buf, labels)
+}
+
+// formatSeconds formats a timestamp as seconds since 1970-01-01 UTC.
+func formatSeconds(t time.Time) string {
+	return strconv.FormatFloat(float64(t.UnixMilli())/1000, 'f', -1, 64)
+}
@@ -0,0 +1,38 @@
+package promout
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	fake "github.com/powerpu/go-fake-ts"
+)
+
+func ExampleOpenMetricsEncoder() {
+	ft, _ := fake.NewTime("ts", time.Date(2020, 2, 7, 0, 0, 0, 0, time.UTC), 15500, 0, 0, false)
+	cpu, _ := fake.NewDataFromConfig(fake.NewDataConfig("cpu", 3, fake.WithSlope(1)))
+	reqs, _ := fake.NewDataFromConfig(fake.NewDataConfig("reqs", 3, fake.WithRange(10, 20)))
+	fp, _ := fake.NewPattern("traced", 1, 1, false)
+
+	e := NewOpenMetricsEncoder(os.Stdout, ft,
+		Metric{Name: "node_cpu", Help: "CPU usage.", Value: cpu},
+		Metric{Name: "http_requests", Kind: Counter, Labels: map[string]string{"code": "200"}, Value: reqs, Exemplars: fp})
+	e.Encode(3)
+	// Output:
+	// # TYPE node_cpu gauge
+	// # HELP node_cpu CPU usage.
+	// node_cpu 50 1581033600
+	// node_cpu 51 1581033615.5
+	// node_cpu 52 1581033631
+	// # TYPE http_requests counter
+	// http_requests_total{code="200"} 15 1581033600 # {trace_id="b403cdd9fddeb3c7"} 15 1581033600
+	// http_requests_total{code="200"} 30 1581033615.5
+	// http_requests_total{code="200"} 45 1581033631 # {trace_id="b403cfd9fddeb72d"} 15 1581033631
+	// # EOF
+}
+
+func ExampleOpenMetricsEncoder_zero() {
+	var e OpenMetricsEncoder
+	fmt.Println(e.Encode(1))
+	// Output: promout: an OpenMetricsEncoder must be created with NewOpenMetricsEncoder
+}
@@ -0,0 +1,352 @@
+// Package promout exposes fake time series as a Prometheus scrape target.
+//
+// A Handler serves a set of metrics in the Prometheus text exposition format
+// and advances them on every scrape or on a fixed interval:
+//
+//  h := promout.NewHandler(
+//      promout.Metric{Name: "node_cpu", Kind: promout.Gauge, Value: fakeData1},
+//      promout.Metric{Name: "http_requests_total", Kind: promout.Counter, Value: fakeData2})
+//  h.Failures = []promout.Failure{{Gate: fakeRandom, Mode: promout.InternalError}}
+//  http.Handle("/metrics", h)
+//
+// Scrapes can be made to fail when a gate is "bad" to simulate a flaky
+// target, and a metric whose gate is "bad" is left out of a scrape to
+// simulate "bad data".
+//
+// The same metrics can be pushed instead: a WriteEncoder encodes them as
+// remote-write WriteRequests (protobuf and snappy) for a Sender to send, and
+// an OpenMetricsEncoder writes them as OpenMetrics text with exemplars.
+package promout
+
+import (
+	"bytes"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	fake "github.com/powerpu/go-fake-ts"
+)
+
+// Kind is the Prometheus type of a metric.
+type Kind int
+
+const (
+	// Gauge exposes the current value of a Value.
+	Gauge Kind = iota
+
+	// Counter exposes the running total of a Value. Every sample is added to
+	// the counter, negative samples are ignored so it never goes down.
+	Counter
+)
+
+// Metric is a single series exposed by a Handler. Metrics with the same name
+// are exposed as one metric family and should only differ by their labels.
+type Metric struct {
+	// The name of the metric.
+	Name string
+
+	// The help text of the metric family. Only the first non empty help text
+	// of a family is used.
+	Help string
+
+	// The type of the metric. Defaults to Gauge.
+	Kind Kind
+
+	// The labels of the metric. They are exposed sorted by name.
+	Labels map[string]string
+
+	// The value of the metric, usually a Data but any Value that returns a
+	// float64 will do.
+	Value fake.Value
+
+	// Decide whether the data of the metric is "good". Optional.
+	Gates []fake.Gate
+
+	// Attaches an exemplar with a "trace_id" label to every sample while it
+	// is "good". Optional. Exemplars are only written by a WriteEncoder and,
+	// for counters, by an OpenMetricsEncoder.
+	Exemplars fake.Gate
+}
+
+// FailureMode is how a scrape fails.
+type FailureMode int
+
+const (
+	// InternalError responds with HTTP 500.
+	InternalError FailureMode = iota
+
+	// Timeout waits for the Delay of the Handler before responding, long
+	// enough for the scraper to give up.
+	Timeout
+
+	// Truncated sends only half of the body even though the Content-Length
+	// header promises all of it.
+	Truncated
+)
+
+// Failure fails a scrape whenever its gate is "bad".
+type Failure struct {
+	// Decides whether a scrape fails. It advances once per scrape.
+	Gate fake.Gate
+
+	// How the scrape fails.
+	Mode FailureMode
+}
+
+// Handler is an http.Handler serving metrics in the Prometheus text
+// exposition format. It can be scraped by many clients concurrently.
+type Handler struct {
+	// The metrics to expose.
+	Metrics []Metric
+
+	// Timestamps of the samples. Optional, samples carry no timestamp
+	// without a Time.
+	Time *fake.Time
+
+	// How often the metrics advance. Zero advances them on every scrape,
+	// otherwise they advance once per elapsed interval since the first
+	// scrape, however many scrapes there are.
+	Interval time.Duration
+
+	// The clock used for Interval and Delay. Defaults to fake.SystemClock.
+	Clock fake.Clock
+
+	// Checked in order on every scrape, the first failure whose gate is
+	// "bad" fails the scrape.
+	Failures []Failure
+
+	// How long a Timeout failure waits. Defaults to 1 minute.
+	Delay time.Duration
+
+	mu       sync.Mutex
+	started  bool
+	start    time.Time
+	steps    int64
+	counters []float64
+}
+
+// NewHandler creates a Handler exposing the given metrics with all defaults
+// set.
+func NewHandler(metrics ...Metric) *Handler {
+	return &Handler{
+		Metrics: metrics,
+		Clock:   fake.SystemClock,
+		Delay:   time.Minute,
+	}
+}
+
+// ServeHTTP serves a single scrape.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	clock := h.Clock
+	if clock == nil {
+		clock = fake.SystemClock
+	}
+
+	body, failure := h.scrape(clock)
+
+	switch failure {
+	case InternalError:
+		http.Error(w, "simulated scrape failure", http.StatusInternalServerError)
+		return
+	case Timeout:
+		delay := h.Delay
+		if delay <= 0 {
+			delay = time.Minute
+		}
+
+		select {
+		case <-clock.After(delay):
+		case <-r.Context().Done():
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+
+	if failure == Truncated {
+		body = body[:len(body)/2]
+	}
+
+	w.Write(body)
+}
+
+// scrape renders the metrics, advances all generators and returns the body
+// and how the scrape fails (-1 if it does not).
+func (h *Handler) scrape(clock fake.Clock) ([]byte, FailureMode) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	// Counters start with the first sample
+	if h.counters == nil {
+		h.counters = make([]float64, len(h.Metrics))
+		h.count()
+	}
+
+	failure := FailureMode(-1)
+	for _, f := range h.Failures {
+		if f.Gate.Bad() {
+			failure = f.Mode
+			break
+		}
+	}
+
+	for _, f := range h.Failures {
+		f.Gate.Next()
+	}
+
+	if h.Interval > 0 {
+		now := clock.Now()
+		if !h.started {
+			h.started = true
+			h.start = now
+		}
+
+		due := int64(now.Sub(h.start) / h.Interval)
+		for ; h.steps < due; h.steps++ {
+			h.next()
+		}
+	}
+
+	body := h.render()
+
+	if h.Interval <= 0 {
+		h.next()
+	}
+
+	return body, failure
+}
+
+// next advances every generator by one sample.
+func (h *Handler) next() {
+	if h.Time != nil {
+		h.Time.Next()
+	}
+
+	for _, m := range h.Metrics {
+		m.Value.Next()
+		for _, g := range m.Gates {
+			g.Next()
+		}
+	}
+
+	h.count()
+}
+
+// count adds the current samples to the counters.
+func (h *Handler) count() {
+	for i, m := range h.Metrics {
+		if m.Kind != Counter {
+			continue
+		}
+
+		if v, ok := m.Value.Val().(float64); ok && v > 0 {
+			h.counters[i] += v
+		}
+	}
+}
+
+// render writes the current samples in the text exposition format.
+func (h *Handler) render() []byte {
+	var ts string
+	if h.Time != nil {
+		ts = " " + strconv.FormatInt(h.Time.Time().UnixMilli(), 10)
+	}
+
+	// Group metrics by family in the order the families first appear
+	var names []string
+	families := map[string][]int{}
+	for i, m := range h.Metrics {
+		if _, ok := families[m.Name]; !ok {
+			names = append(names, m.Name)
+		}
+
+		families[m.Name] = append(families[m.Name], i)
+	}
+
+	var buf bytes.Buffer
+	for _, name := range names {
+		family := families[name]
+
+		for _, i := range family {
+			if h.Metrics[i].Help != "" {
+				buf.WriteString("# HELP " + name + " " + helpEscaper.Replace(h.Metrics[i].Help) + "\n")
+				break
+			}
+		}
+
+		kind := "gauge"
+		if h.Metrics[family[0]].Kind == Counter {
+			kind = "counter"
+		}
+		buf.WriteString("# TYPE " + name + " " + kind + "\n")
+
+		for _, i := range family {
+			m := h.Metrics[i]
+			if !fake.AllGood(m.Gates...) {
+				continue
+			}
+
+			v, ok := m.Value.Val().(float64)
+			if !ok {
+				continue
+			}
+
+			if m.Kind == Counter {
+				v = h.counters[i]
+			}
+
+			buf.WriteString(name)
+			writeLabels(&buf, m.Labels)
+			buf.WriteString(" " + formatFloat(v) + ts + "\n")
+		}
+	}
+
+	return buf.Bytes()
+}
+
+var (
+	helpEscaper  = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+	labelEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+)
+
+// writeLabels writes the labels sorted by name.
+func writeLabels(buf *bytes.Buffer, labels map[string]string) {
+	if len(labels) == 0 {
+		return
+	}
+
+	names := make([]string, 0, len(labels))
+	for name := range labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	buf.WriteByte('{')
+	for i, name := range names {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+
+		buf.WriteString(name + `="` + labelEscaper.Replace(labels[name]) + `"`)
+	}
+	buf.WriteByte('}')
+}
+
+// formatFloat formats a sample value the way Prometheus does.
+func formatFloat(v float64) string {
+	switch {
+	case math.IsNaN(v):
+		return "NaN"
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	}
+
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
@@ -0,0 +1,148 @@
+package promout
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	fake "github.com/powerpu/go-fake-ts"
+)
+
+// scrape fetches the url and prints the status and body or the error.
+func scrape(client *http.Client, url string) {
+	resp, err := client.Get(url)
+	if err != nil {
+		fmt.Println("error")
+		return
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		fmt.Printf("%v: %v\n", resp.StatusCode, err)
+		return
+	}
+
+	fmt.Printf("%v:\n%s", resp.StatusCode, body)
+}
+
+func ExampleHandler() {
+	ft, _ := fake.NewTime("ts", time.Date(2020, 2, 7, 0, 0, 0, 0, time.UTC), 15000, 0, 0, false)
+	cpu1, _ := fake.NewDataFromConfig(fake.NewDataConfig("cpu1", 3, fake.WithSlope(1)))
+	cpu2, _ := fake.NewDataFromConfig(fake.NewDataConfig("cpu2", 3, fake.WithSlope(2)))
+	reqs, _ := fake.NewDataFromConfig(fake.NewDataConfig("reqs", 3, fake.WithRange(10, 20)))
+	fp, _ := fake.NewPattern("cpu2Gate", 1, 1, false)
+
+	h := NewHandler(
+		Metric{Name: "node_cpu", Help: "CPU usage.", Labels: map[string]string{"cpu": "1"}, Value: cpu1},
+		Metric{Name: "node_cpu", Labels: map[string]string{"cpu": "2"}, Value: cpu2, Gates: []fake.Gate{fp}},
+		Metric{Name: "http_requests_total", Kind: Counter, Labels: map[string]string{"path": `/a "b"`}, Value: reqs})
+	h.Time = ft
+
+	srv := httptest.NewServer(h)
+	defer srv.Close()
+
+	scrape(srv.Client(), srv.URL)
+	scrape(srv.Client(), srv.URL)
+	// Output:
+	// 200:
+	// # HELP node_cpu CPU usage.
+	// # TYPE node_cpu gauge
+	// node_cpu{cpu="1"} 50 1581033600000
+	// node_cpu{cpu="2"} 50 1581033600000
+	// # TYPE http_requests_total counter
+	// http_requests_total{path="/a \"b\""} 15 1581033600000
+	// 200:
+	// # HELP node_cpu CPU usage.
+	// # TYPE node_cpu gauge
+	// node_cpu{cpu="1"} 51 1581033615000
+	// # TYPE http_requests_total counter
+	// http_requests_total{path="/a \"b\""} 30 1581033615000
+}
+
+func ExampleHandler_failures() {
+	cpu, _ := fake.NewDataFromConfig(fake.NewDataConfig("cpu", 5, fake.WithSlope(1)))
+	errors, _ := fake.NewPattern("errors", 1, 1, false)
+	truncated, _ := fake.NewPattern("truncated", 2, 1, false)
+
+	h := NewHandler(Metric{Name: "node_cpu", Value: cpu})
+	h.Failures = []Failure{{Gate: errors, Mode: InternalError}, {Gate: truncated, Mode: Truncated}}
+
+	srv := httptest.NewServer(h)
+	defer srv.Close()
+
+	for i := 0; i < 4; i++ {
+		scrape(srv.Client(), srv.URL)
+	}
+	// Output:
+	// 200:
+	// # TYPE node_cpu gauge
+	// node_cpu 50
+	// 500:
+	// simulated scrape failure
+	// 200: unexpected EOF
+	// 500:
+	// simulated scrape failure
+}
+
+func ExampleHandler_timeout() {
+	cpu, _ := fake.NewDataFromConfig(fake.NewDataConfig("cpu", 5, fake.WithSlope(1)))
+	fp, _ := fake.NewPattern("timeouts", 0, 1, false)
+
+	h := NewHandler(Metric{Name: "node_cpu", Value: cpu})
+	h.Failures = []Failure{{Gate: fp, Mode: Timeout}}
+	h.Delay = time.Hour
+
+	srv := httptest.NewServer(h)
+	defer srv.Close()
+
+	client := srv.Client()
+	client.Timeout = 50 * time.Millisecond
+	scrape(client, srv.URL)
+	// Output: error
+}
+
+// testClock only moves when told to.
+type testClock struct {
+	now time.Time
+}
+
+func (tc *testClock) Now() time.Time { return tc.now }
+
+func (tc *testClock) After(d time.Duration) <-chan time.Time {
+	tc.now = tc.now.Add(d)
+	ch := make(chan time.Time, 1)
+	ch <- tc.now
+	return ch
+}
+
+func ExampleHandler_interval() {
+	cpu, _ := fake.NewDataFromConfig(fake.NewDataConfig("cpu", 10, fake.WithSlope(1)))
+	clock := &testClock{now: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)}
+
+	h := NewHandler(Metric{Name: "node_cpu", Value: cpu})
+	h.Interval = 10 * time.Second
+	h.Clock = clock
+
+	srv := httptest.NewServer(h)
+	defer srv.Close()
+
+	// Two scrapes within the same interval see the same sample
+	scrape(srv.Client(), srv.URL)
+	clock.now = clock.now.Add(5 * time.Second)
+	scrape(srv.Client(), srv.URL)
+	clock.now = clock.now.Add(30 * time.Second)
+	scrape(srv.Client(), srv.URL)
+	// Output:
+	// 200:
+	// # TYPE node_cpu gauge
+	// node_cpu 50
+	// 200:
+	// # TYPE node_cpu gauge
+	// node_cpu 50
+	// 200:
+	// # TYPE node_cpu gauge
+	// node_cpu 53
+}
@@ -0,0 +1,348 @@
+package promout
+
+import (
+	"errors"
+	"math"
+	"time"
+
+	"github.com/golang/snappy"
+	fake "github.com/powerpu/go-fake-ts"
+)
+
+// TimeSeries is a single series of a Prometheus remote-write WriteRequest.
+type TimeSeries struct {
+	// The labels of the series including its name as "__name__".
+	Labels []Label
+
+	// The samples of the series in timestamp order.
+	Samples []Sample
+
+	// The exemplars of the series in timestamp order.
+	Exemplars []Exemplar
+}
+
+// Label is a single label of a TimeSeries.
+type Label struct {
+	Name  string
+	Value string
+}
+
+// Sample is a single sample of a TimeSeries.
+type Sample struct {
+	Value     float64
+	Timestamp time.Time
+}
+
+// Exemplar is an example observation attached to a sample, usually linking
+// it to a trace.
+type Exemplar struct {
+	Labels    map[string]string
+	Value     float64
+	Timestamp time.Time
+}
+
+// WriteEncoder encodes a Time and a set of metrics as Prometheus remote-write
+// WriteRequests. Every call to Encode advances the Time, the gates and all
+// metrics in lock-step so none of them may appear twice or be used elsewhere
+// while encoding.
+type WriteEncoder struct {
+	// The timestamp of every sample.
+	Time *fake.Time
+
+	// Decide whether a sample is "good" for all metrics. Optional.
+	Gates []fake.Gate
+
+	// The metrics to encode. A sample whose gate is "bad" is left out.
+	Metrics []Metric
+
+	s sampler
+}
+
+// NewWriteEncoder creates a WriteEncoder for the given metrics.
+func NewWriteEncoder(ft *fake.Time, metrics ...Metric) *WriteEncoder {
+	return &WriteEncoder{Time: ft, Metrics: metrics}
+}
+
+// Series generates the next count samples of every metric as time series.
+// Metrics without any samples are left out.
+func (e *WriteEncoder) Series(count int) []TimeSeries {
+	points := e.s.sample(e.Time, e.Gates, e.Metrics, count)
+
+	var series []TimeSeries
+	for i, m := range e.Metrics {
+		if len(points[i]) == 0 {
+			continue
+		}
+
+		labels := make(map[string]string, len(m.Labels)+1)
+		for name, value := range m.Labels {
+			labels[name] = value
+		}
+		labels["__name__"] = m.Name
+
+		ts := TimeSeries{}
+		for _, name := range sortedNames(labels) {
+			ts.Labels = append(ts.Labels, Label{Name: name, Value: labels[name]})
+		}
+
+		for _, p := range points[i] {
+			ts.Samples = append(ts.Samples, Sample{Value: p.value, Timestamp: p.ts})
+			if p.exemplar != nil {
+				ts.Exemplars = append(ts.Exemplars, *p.exemplar)
+			}
+		}
+
+		series = append(series, ts)
+	}
+
+	return series
+}
+
+// Encode generates the next count samples of every metric as a single
+// snappy compressed WriteRequest, ready to be sent.
+func (e *WriteEncoder) Encode(count int) []byte {
+	return EncodeWriteRequest(e.Series(count))
+}
+
+// EncodeWriteRequest encodes time series as a snappy compressed
+// WriteRequest.
+func EncodeWriteRequest(series []TimeSeries) []byte {
+	var b []byte
+	for _, ts := range series {
+		b = appendMessage(b, 1, appendTimeSeries(nil, ts))
+	}
+
+	return snappy.Encode(nil, b)
+}
+
+// DecodeWriteRequest decodes a snappy compressed WriteRequest, e.g. in a
+// stand-in receiver. Exemplar labels are decoded, any other unknown fields
+// are skipped.
+func DecodeWriteRequest(body []byte) ([]TimeSeries, error) {
+	b, err := snappy.Decode(nil, body)
+	if err != nil {
+		return nil, err
+	}
+
+	var series []TimeSeries
+	err = decodeMessage(b, func(field int, v []byte, _ uint64) error {
+		if field != 1 {
+			return nil
+		}
+
+		ts, err := decodeTimeSeries(v)
+		series = append(series, ts)
+		return err
+	})
+
+	return series, err
+}
+
+// The protobuf wire types used by WriteRequest
+const (
+	wireVarint  = 0
+	wireFixed64 = 1
+	wireBytes   = 2
+)
+
+func appendVarint(b []byte, v uint64) []byte {
+	for v >= 0x80 {
+		b = append(b, byte(v)|0x80)
+		v >>= 7
+	}
+
+	return append(b, byte(v))
+}
+
+func appendTag(b []byte, field int, wire int) []byte {
+	return appendVarint(b, uint64(field)<<3|uint64(wire))
+}
+
+func appendMessage(b []byte, field int, msg []byte) []byte {
+	b = appendTag(b, field, wireBytes)
+	b = appendVarint(b, uint64(len(msg)))
+	return append(b, msg...)
+}
+
+func appendDouble(b []byte, field int, v float64) []byte {
+	b = appendTag(b, field, wireFixed64)
+	u := math.Float64bits(v)
+	for i := 0; i < 8; i++ {
+		b = append(b, byte(u>>(8*i)))
+	}
+
+	return b
+}
+
+func appendInt64(b []byte, field int, v int64) []byte {
+	b = appendTag(b, field, wireVarint)
+	return appendVarint(b, uint64(v))
+}
+
+func appendLabel(b []byte, field int, name string, value string) []byte {
+	var l []byte
+	l = appendMessage(l, 1, []byte(name))
+	l = appendMessage(l, 2, []byte(value))
+	return appendMessage(b, field, l)
+}
+
+// appendTimeSeries encodes a TimeSeries: labels are field 1, samples field
+// 2 and exemplars field 3.
+func appendTimeSeries(b []byte, ts TimeSeries) []byte {
+	for _, l := range ts.Labels {
+		b = appendLabel(b, 1, l.Name, l.Value)
+	}
+
+	for _, s := range ts.Samples {
+		var sb []byte
+		sb = appendDouble(sb, 1, s.Value)
+		sb = appendInt64(sb, 2, s.Timestamp.UnixMilli())
+		b = appendMessage(b, 2, sb)
+	}
+
+	for _, e := range ts.Exemplars {
+		var eb []byte
+		for _, name := range sortedNames(e.Labels) {
+			eb = appendLabel(eb, 1, name, e.Labels[name])
+		}
+		eb = appendDouble(eb, 2, e.Value)
+		eb = appendInt64(eb, 3, e.Timestamp.UnixMilli())
+		b = appendMessage(b, 3, eb)
+	}
+
+	return b
+}
+
+var errMalformed = errors.New("malformed remote-write WriteRequest")
+
+// decodeMessage calls fn for every field of a message with either its bytes
+// or its varint or fixed64 value.
+func decodeMessage(b []byte, fn func(field int, v []byte, u uint64) error) error {
+	for len(b) > 0 {
+		tag, n := decodeVarint(b)
+		if n == 0 {
+			return errMalformed
+		}
+		b = b[n:]
+
+		field := int(tag >> 3)
+		switch tag & 7 {
+		case wireVarint:
+			u, n := decodeVarint(b)
+			if n == 0 {
+				return errMalformed
+			}
+			b = b[n:]
+
+			if err := fn(field, nil, u); err != nil {
+				return err
+			}
+		case wireFixed64:
+			if len(b) < 8 {
+				return errMalformed
+			}
+
+			var u uint64
+			for i := 0; i < 8; i++ {
+				u |= uint64(b[i]) << (8 * i)
+			}
+			b = b[8:]
+
+			if err := fn(field, nil, u); err != nil {
+				return err
+			}
+		case wireBytes:
+			l, n := decodeVarint(b)
+			if n == 0 || uint64(len(b)-n) < l {
+				return errMalformed
+			}
+
+			v := b[n : n+int(l)]
+			b = b[n+int(l):]
+
+			if err := fn(field, v, 0); err != nil {
+				return err
+			}
+		default:
+			return errMalformed
+		}
+	}
+
+	return nil
+}
+
+func decodeVarint(b []byte) (uint64, int) {
+	var v uint64
+	for i := 0; i < len(b) && i < 10; i++ {
+		v |= uint64(b[i]&0x7f) << (7 * i)
+		if b[i] < 0x80 {
+			return v, i + 1
+		}
+	}
+
+	return 0, 0
+}
+
+func decodeLabel(b []byte) (Label, error) {
+	var l Label
+	err := decodeMessage(b, func(field int, v []byte, _ uint64) error {
+		switch field {
+		case 1:
+			l.Name = string(v)
+		case 2:
+			l.Value = string(v)
+		}
+
+		return nil
+	})
+
+	return l, err
+}
+
+func decodeTimeSeries(b []byte) (TimeSeries, error) {
+	var ts TimeSeries
+	err := decodeMessage(b, func(field int, v []byte, _ uint64) error {
+		switch field {
+		case 1:
+			l, err := decodeLabel(v)
+			ts.Labels = append(ts.Labels, l)
+			return err
+		case 2:
+			var s Sample
+			err := decodeMessage(v, func(field int, _ []byte, u uint64) error {
+				switch field {
+				case 1:
+					s.Value = math.Float64frombits(u)
+				case 2:
+					s.Timestamp = time.UnixMilli(int64(u)).UTC()
+				}
+
+				return nil
+			})
+			ts.Samples = append(ts.Samples, s)
+			return err
+		case 3:
+			e := Exemplar{Labels: map[string]string{}}
+			err := decodeMessage(v, func(field int, v []byte, u uint64) error {
+				switch field {
+				case 1:
+					l, err := decodeLabel(v)
+					e.Labels[l.Name] = l.Value
+					return err
+				case 2:
+					e.Value = math.Float64frombits(u)
+				case 3:
+					e.Timestamp = time.UnixMilli(int64(u)).UTC()
+				}
+
+				return nil
+			})
+			ts.Exemplars = append(ts.Exemplars, e)
+			return err
+		}
+
+		return nil
+	})
+
+	return ts, err
+}
@@ -0,0 +1,41 @@
+package promout
+
+import (
+	"fmt"
+	"time"
+
+	fake "github.com/powerpu/go-fake-ts"
+)
+
+func ExampleWriteEncoder() {
+	ft, _ := fake.NewTime("ts", time.Date(2020, 2, 7, 0, 0, 0, 0, time.UTC), 15000, 0, 0, false)
+	cpu, _ := fake.NewDataFromConfig(fake.NewDataConfig("cpu", 3, fake.WithSlope(1)))
+	reqs, _ := fake.NewDataFromConfig(fake.NewDataConfig("reqs", 3, fake.WithRange(10, 20)))
+	fp, _ := fake.NewPattern("traced", 1, 1, false)
+
+	e := NewWriteEncoder(ft,
+		Metric{Name: "node_cpu", Labels: map[string]string{"instance": "host-1"}, Value: cpu},
+		Metric{Name: "http_requests_total", Kind: Counter, Value: reqs, Exemplars: fp})
+
+	series, _ := DecodeWriteRequest(e.Encode(3))
+	for _, ts := range series {
+		fmt.Println(ts.Labels)
+		for _, s := range ts.Samples {
+			fmt.Println(" ", s.Value, s.Timestamp.Format(time.TimeOnly))
+		}
+		for _, e := range ts.Exemplars {
+			fmt.Println(" ", e.Labels, e.Value, e.Timestamp.Format(time.TimeOnly))
+		}
+	}
+	// Output:
+	// [{__name__ node_cpu} {instance host-1}]
+	//   50 00:00:00
+	//   51 00:00:15
+	//   52 00:00:30
+	// [{__name__ http_requests_total}]
+	//   15 00:00:00
+	//   30 00:00:15
+	//   45 00:00:30
+	//   map[trace_id:252e2ba66c1c95ea] 15 00:00:00
+	//   map[trace_id:252e29a66c1c9284] 15 00:00:30
+}
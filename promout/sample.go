@@ -0,0 +1,109 @@
+package promout
+
+import (
+	"hash/fnv"
+	"sort"
+	"strconv"
+	"time"
+
+	fake "github.com/powerpu/go-fake-ts"
+)
+
+// point is a single sample of a metric.
+type point struct {
+	value    float64
+	ts       time.Time
+	exemplar *Exemplar
+}
+
+// sampler generates the samples of a set of metrics over a Time. Counters
+// add up every sample just like the counters of a Handler.
+type sampler struct {
+	counters []float64
+	i        int64
+}
+
+// sample generates the next count samples of every metric, grouped by
+// metric, and advances every generator.
+func (s *sampler) sample(ft *fake.Time, gates []fake.Gate, metrics []Metric, count int) [][]point {
+	if s.counters == nil {
+		s.counters = make([]float64, len(metrics))
+	}
+
+	points := make([][]point, len(metrics))
+	for n := 0; n < count; n++ {
+		good := fake.AllGood(gates...)
+		for j, m := range metrics {
+			v, ok := m.Value.Val().(float64)
+			if !ok {
+				continue
+			}
+
+			if m.Kind == Counter {
+				if v > 0 {
+					s.counters[j] += v
+				}
+				v = s.counters[j]
+			}
+
+			if !good || !fake.AllGood(m.Gates...) {
+				continue
+			}
+
+			p := point{value: v, ts: ft.Time()}
+			if m.Exemplars != nil && m.Exemplars.Good() {
+				p.exemplar = &Exemplar{
+					Labels:    map[string]string{"trace_id": traceID(m.Name, s.i)},
+					Value:     m.Value.Val().(float64),
+					Timestamp: p.ts,
+				}
+			}
+
+			points[j] = append(points[j], p)
+		}
+
+		ft.Next()
+		for _, g := range gates {
+			g.Next()
+		}
+
+		for _, m := range metrics {
+			m.Value.Next()
+			for _, g := range m.Gates {
+				g.Next()
+			}
+
+			if m.Exemplars != nil {
+				m.Exemplars.Next()
+			}
+		}
+
+		s.i++
+	}
+
+	return points
+}
+
+// traceID derives a stable trace ID for the exemplar of a sample.
+func traceID(name string, i int64) string {
+	h := fnv.New64a()
+	h.Write([]byte(name + "/" + strconv.FormatInt(i, 10)))
+
+	id := strconv.FormatUint(h.Sum64(), 16)
+	for len(id) < 16 {
+		id = "0" + id
+	}
+
+	return id
+}
+
+// sortedNames returns the names of the labels in order.
+func sortedNames(labels map[string]string) []string {
+	names := make([]string, 0, len(labels))
+	for name := range labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
@@ -0,0 +1,125 @@
+package promout
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	fake "github.com/powerpu/go-fake-ts"
+)
+
+// Sender sends remote-write WriteRequests to a receiver, retrying with an
+// exponential backoff when the receiver is unavailable.
+type Sender struct {
+	// The URL of the receiver, e.g. "http://localhost:9090/api/v1/write".
+	URL string
+
+	// The client used to send requests. Defaults to http.DefaultClient.
+	Client *http.Client
+
+	// How often a request is retried after it failed. Defaults to 3, a
+	// negative value never retries.
+	Retries int
+
+	// How long to wait before the first retry. The wait doubles with every
+	// retry. Defaults to 100 milliseconds.
+	Backoff time.Duration
+
+	// The longest wait between two retries. Defaults to 10 seconds.
+	MaxBackoff time.Duration
+
+	// The clock used to wait between retries. Defaults to fake.SystemClock.
+	Clock fake.Clock
+}
+
+// NewSender creates a Sender sending to url with all defaults set.
+func NewSender(url string) *Sender {
+	return &Sender{
+		URL:        url,
+		Client:     http.DefaultClient,
+		Retries:    3,
+		Backoff:    100 * time.Millisecond,
+		MaxBackoff: 10 * time.Second,
+		Clock:      fake.SystemClock,
+	}
+}
+
+// Send sends a snappy compressed WriteRequest (see WriteEncoder.Encode).
+// Requests failing with a network error, HTTP 429 or HTTP 5xx are retried,
+// any other failure is returned straight away.
+func (s *Sender) Send(ctx context.Context, body []byte) error {
+	client := s.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	clock := s.Clock
+	if clock == nil {
+		clock = fake.SystemClock
+	}
+
+	backoff := s.Backoff
+	if backoff <= 0 {
+		backoff = 100 * time.Millisecond
+	}
+
+	maxBackoff := s.MaxBackoff
+	if maxBackoff <= 0 {
+		maxBackoff = 10 * time.Second
+	}
+
+	retries := s.Retries
+	if retries == 0 {
+		retries = 3
+	}
+
+	for attempt := 0; ; attempt++ {
+		retry, err := s.send(ctx, client, body)
+		if err == nil || !retry || attempt >= retries {
+			return err
+		}
+
+		select {
+		case <-clock.After(backoff):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+
+		backoff *= 2
+		if backoff > maxBackoff {
+			backoff = maxBackoff
+		}
+	}
+}
+
+// send makes a single attempt and returns whether it is worth retrying.
+func (s *Sender) send(ctx context.Context, client *http.Client, body []byte) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.URL, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+
+	req.Header.Set("Content-Encoding", "snappy")
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	req.Header.Set("X-Prometheus-Remote-Write-Version", "0.1.0")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return ctx.Err() == nil, err
+	}
+	defer resp.Body.Close()
+
+	// Drain the body so the connection can be reused
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode/100 == 2 {
+		return false, nil
+	}
+
+	err = errors.New("remote write to '" + s.URL + "' failed with status " + strconv.Itoa(resp.StatusCode))
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode/100 == 5, err
+}
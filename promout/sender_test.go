@@ -0,0 +1,75 @@
+package promout
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	fake "github.com/powerpu/go-fake-ts"
+)
+
+func ExampleSender() {
+	ft, _ := fake.NewTime("ts", time.Date(2020, 2, 7, 0, 0, 0, 0, time.UTC), 15000, 0, 0, false)
+	cpu, _ := fake.NewDataFromConfig(fake.NewDataConfig("cpu", 10, fake.WithSlope(1)))
+	e := NewWriteEncoder(ft, Metric{Name: "node_cpu", Value: cpu})
+
+	// A stand-in receiver that is unavailable for the first two requests
+	requests := 0
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests <= 2 {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+
+		body, _ := io.ReadAll(r.Body)
+		series, _ := DecodeWriteRequest(body)
+		fmt.Printf("received %v samples\n", len(series[0].Samples))
+	}))
+	defer receiver.Close()
+
+	clock := &testClock{now: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)}
+	s := NewSender(receiver.URL)
+	s.Clock = clock
+
+	err := s.Send(context.Background(), e.Encode(10))
+	fmt.Println(err, clock.now.Format("15:04:05.000"))
+
+	// A receiver rejecting the request is not retried
+	rejecting := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "bad request", http.StatusBadRequest)
+	}))
+	defer rejecting.Close()
+
+	s.URL = rejecting.URL
+	err = s.Send(context.Background(), e.Encode(10))
+	fmt.Println(err != nil, clock.now.Format("15:04:05.000"))
+	// Output:
+	// received 10 samples
+	// <nil> 00:00:00.300
+	// true 00:00:00.300
+}
+
+// A zero Sender retries 3 times, a negative Retries never retries.
+func ExampleSender_retries() {
+	requests := 0
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	defer receiver.Close()
+
+	for _, retries := range []int{0, -1, 1} {
+		requests = 0
+		s := &Sender{URL: receiver.URL, Retries: retries, Clock: &testClock{}}
+		err := s.Send(context.Background(), nil)
+		fmt.Println(retries, requests, err != nil)
+	}
+	// Output:
+	// 0 4 true
+	// -1 1 true
+	// 1 2 true
+}
@@ -4,13 +4,12 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"math/rand"
 )
 
 // Random generates true/false values based on a predetermined percentage.
 type Random struct {
 	id        string
-	rnd       *rand.Rand
+	rnd       source
 	pctGood   float64
 	keepStats bool
 	Stats     *RandomStats
@@ -46,23 +45,67 @@ type RandomStats struct {
 
 	// Slot ratio of good/bad. This gets reset after every JSON() call.
 	Ratio float64 `json:"slotGoodRatio"`
+
+	// Cumulative count of bursts, i.e. runs of "bad" values.
+	CBursts int64 `json:"cumulativeBursts"`
+
+	// Cumulative mean length of a burst.
+	CMeanBurst float64 `json:"cumulativeMeanBurst"`
+
+	// Cumulative length of the longest burst.
+	CMaxBurst int64 `json:"cumulativeMaxBurst"`
+
+	// Slot count of bursts that started in the slot. This gets reset after every JSON() call.
+	Bursts int64 `json:"slotBursts"`
+
+	// Slot mean length of a burst. This gets reset after every JSON() call.
+	MeanBurst float64 `json:"slotMeanBurst"`
+
+	// Slot length of the longest burst. This gets reset after every JSON() call.
+	MaxBurst int64 `json:"slotMaxBurst"`
+
+	// Length of the current burst, 0 when the last value was "good".
+	Burst int64 `json:"currentBurst"`
 }
 
 // Add adds a value to the running tally.
 func (rs *RandomStats) Add(v interface{}) {
+	rs.add(v.(bool))
+}
+
+// add adds a value to the running tally without boxing it.
+func (rs *RandomStats) add(v bool) {
 	rs.CTotal++
 	rs.Total++
 
-	if v.(bool) {
+	if v {
 		rs.CGoodCount++
 		rs.GoodCount++
+		rs.Burst = 0
 	} else {
 		rs.CBadCount++
 		rs.BadCount++
+
+		rs.Burst++
+		if rs.Burst == 1 {
+			rs.CBursts++
+			rs.Bursts++
+		}
+
+		rs.CMaxBurst = max(rs.CMaxBurst, rs.Burst)
+		rs.MaxBurst = max(rs.MaxBurst, rs.Burst)
 	}
 
 	rs.CRatio = float64(rs.CGoodCount) / float64(rs.CTotal)
 	rs.Ratio = float64(rs.GoodCount) / float64(rs.Total)
+
+	if rs.CBursts > 0 {
+		rs.CMeanBurst = float64(rs.CBadCount) / float64(rs.CBursts)
+	}
+
+	if rs.Bursts > 0 {
+		rs.MeanBurst = float64(rs.BadCount) / float64(rs.Bursts)
+	}
 }
 
 // JSON returns a JSON summary of the current random statistics and resets the
@@ -73,6 +116,9 @@ func (rs *RandomStats) JSON() string {
 	rs.GoodCount = 0
 	rs.BadCount = 0
 	rs.Ratio = 0
+	rs.Bursts = 0
+	rs.MeanBurst = 0
+	rs.MaxBurst = 0
 	return string(out)
 
 }
@@ -81,7 +127,7 @@ func (rs *RandomStats) JSON() string {
 func (fr *Random) Next() {
 	fr.v = fr.rnd.Float64() < fr.pctGood
 	if fr.keepStats {
-		fr.Stats.Add(fr.v)
+		fr.Stats.add(fr.v)
 	}
 }
 
@@ -100,6 +146,75 @@ func (fr *Random) JSONStats() string {
 	return fr.Stats.JSON()
 }
 
+// ID returns the unique id.
+func (fr *Random) ID() string {
+	return fr.id
+}
+
+// PctGood returns the percentage (0 to 1) of values that are "good".
+func (fr *Random) PctGood() float64 {
+	return fr.pctGood
+}
+
+// randomState is the runtime state of a Random saved by Snapshot().
+type randomState struct {
+	ID     string      `json:"id"`
+	Source sourceState `json:"source"`
+	V      bool        `json:"v"`
+	Stats  RandomStats `json:"stats"`
+}
+
+// Snapshot saves the runtime state of the random, including the position of
+// its random numbers and its statistics, so it can be restored later with
+// Restore().
+func (fr *Random) Snapshot() ([]byte, error) {
+	return json.Marshal(randomState{ID: fr.id, Source: fr.rnd.state(), V: fr.v, Stats: *fr.Stats})
+}
+
+// Restore restores the runtime state saved by Snapshot(). The random must
+// have been created with the same parameters as the one that was saved and
+// will continue with the identical sequence.
+func (fr *Random) Restore(b []byte) error {
+	var st randomState
+	if err := json.Unmarshal(b, &st); err != nil {
+		return err
+	}
+
+	if st.ID != fr.id {
+		return errors.New("cannot restore a fake random with id '" + fr.id + "' from a snapshot of id '" + st.ID + "'")
+	}
+
+	fr.rnd.restore(st.Source)
+	fr.v = st.V
+	*fr.Stats = st.Stats
+	return nil
+}
+
+// Index returns the position of the current value, the first sample being 0.
+func (fr *Random) Index() int64 {
+	return int64(fr.rnd.state().N) - 1
+}
+
+// SeekTo jumps to sample n (the first sample being 0). The random numbers in
+// between are still drawn but nothing else is calculated. Only the sample
+// jumped to is added to the statistics.
+func (fr *Random) SeekTo(n int64) {
+	st := fr.rnd.state()
+	st.N = uint64(n)
+	fr.rnd.restore(st)
+	fr.Next()
+}
+
+// At returns the value of sample n (the first sample being 0) without
+// changing the current value.
+func (fr *Random) At(n int64) bool {
+	st := fr.rnd.state()
+	defer fr.rnd.restore(st)
+
+	fr.rnd.restore(sourceState{Seed: st.Seed, N: uint64(n)})
+	return fr.rnd.Float64() < fr.pctGood
+}
+
 // Good returns whether the current value is "good".
 func (fr *Random) Good() bool {
 	return fr.v
@@ -113,13 +228,26 @@ func (fr *Random) Bad() bool {
 // Values returns the next count of values as a bool array.
 func (fr *Random) Values(count int) []bool {
 	out := make([]bool, count)
+	fr.Fill(out)
+	return out
+}
 
-	for i := 0; i < count; i++ {
-		out[i] = fr.Good()
+// Step returns the current value and generates the next one.
+func (fr *Random) Step() bool {
+	out := fr.Good()
+	fr.Next()
+	return out
+}
+
+// Fill writes the next len(dst) values into dst without allocating and
+// returns how many were written.
+func (fr *Random) Fill(dst []bool) int {
+	for i := range dst {
+		dst[i] = fr.Good()
 		fr.Next()
 	}
 
-	return out
+	return len(dst)
 }
 
 // NewRandom creates a new Random. A random has a unique id, a random seed to
@@ -137,7 +265,7 @@ func NewRandom(id string, seed int64, pctGood float64, keepStats bool) (*Random,
 
 	r := &Random{
 		id:        id,
-		rnd:       generateRandom(seed),
+		rnd:       generateRandom(seed, false),
 		pctGood:   pctGood,
 		keepStats: keepStats,
 		Stats:     &RandomStats{ID: id},
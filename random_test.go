@@ -19,3 +19,14 @@ func ExampleNewRandom_b() {
 	}
 	// Output: true true true true true true true true true true
 }
+
+func ExampleRandom_Snapshot() {
+	fr1, _ := NewRandom("fakeRandom3", 4, 0.5, true)
+	fr1.Vals(5)
+	snapshot, _ := fr1.Snapshot()
+
+	fr2, _ := NewRandom("fakeRandom3", 4, 0.5, true)
+	fr2.Restore(snapshot)
+	fmt.Printf("%v\n", fr2.Vals(5))
+	// Output: [true false false false false]
+}
@@ -0,0 +1,38 @@
+package fake
+
+// regression is a running least squares linear regression. It follows
+// http://www.johndcook.com/running_regression.html and, unlike most
+// implementations, keeps its state in exported fields so it can be saved
+// with a Snapshot() and restored later.
+type regression struct {
+	N     int     `json:"n"`
+	MeanX float64 `json:"meanX"`
+	MeanY float64 `json:"meanY"`
+	M2X   float64 `json:"m2x"`
+	SumXY float64 `json:"sumXY"`
+}
+
+func newRegression() *regression {
+	return &regression{}
+}
+
+// Push adds a point to the running regression.
+func (r *regression) Push(x, y float64) {
+	r.SumXY += (r.MeanX - x) * (r.MeanY - y) * float64(r.N) / float64(r.N+1)
+
+	n1 := float64(r.N)
+	r.N++
+
+	delta := x - r.MeanX
+	deltaN := delta / float64(r.N)
+	r.MeanX += deltaN
+	r.M2X += delta * deltaN * n1
+
+	r.MeanY += (y - r.MeanY) / float64(r.N)
+}
+
+// Slope returns the m in y = m*x + b
+func (r *regression) Slope() float64 {
+	sumXX := (r.M2X / float64(r.N-1)) * float64(r.N-1)
+	return r.SumXY / sumXX
+}
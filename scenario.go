@@ -0,0 +1,733 @@
+package fake
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ScenarioSpec describes a whole simulation: one Time, any number of sample
+// gates and any number of Data series. It can be loaded from JSON or YAML.
+//
+// A YAML scenario may look as follows:
+//
+//  samples: 1000
+//  time:
+//    id: ts
+//    start: 2020-02-07T00:00:00Z
+//    increment: 3600000
+//  gates:
+//    - id: nightly
+//      type: pattern
+//      good: 23
+//      bad: 1
+//  data:
+//    - id: cpu
+//      from: 0
+//      to: 100
+//      useRandom: true
+//      seed: 1
+//      gates:
+//        - id: cpu-collector
+//          type: random
+//          seed: 4
+//          pctGood: 0.99
+type ScenarioSpec struct {
+	// Default number of samples for Data series that do not set their own.
+	Samples int64 `json:"samples" yaml:"samples"`
+
+	// When set every seed is derived from this master seed and the ID of the
+	// generator (see SeedSource) so adding a generator never changes the
+	// values of the others. Seeds of individual generators must not be set.
+	MasterSeed *int64 `json:"masterSeed" yaml:"masterSeed"`
+
+	// The time of every row.
+	Time TimeConfig `json:"time" yaml:"time"`
+
+	// Gates that decide whether a whole sample is "good" or "bad".
+	Gates []GateSpec `json:"gates" yaml:"gates"`
+
+	// The Data series of every row.
+	Data []DataSpec `json:"data" yaml:"data"`
+}
+
+// GateSpec describes a Pattern (type "pattern"), a Random (type "random"), a
+// BurstRandom (type "burst") or a Combinator of other gates (type "and", "or",
+// "xor", "not", "atLeast" or "majority"). See NewPattern, NewRandom,
+// NewBurstRandom and Combinator for what each field does.
+type GateSpec struct {
+	ID        string  `json:"id" yaml:"id"`
+	Type      string  `json:"type" yaml:"type"`
+	Good      int     `json:"good" yaml:"good"`
+	Bad       int     `json:"bad" yaml:"bad"`
+	Seed      int64   `json:"seed" yaml:"seed"`
+	PctGood   float64 `json:"pctGood" yaml:"pctGood"`
+	MeanBurst float64 `json:"meanBurst" yaml:"meanBurst"`
+	KeepStats bool    `json:"keepStats" yaml:"keepStats"`
+
+	// A pattern can also be a sequence (see PatternConfig) or daily windows
+	// of bad samples for the time of the scenario (see NewTimePattern).
+	Sequence string   `json:"sequence" yaml:"sequence"`
+	Phase    int64    `json:"phase" yaml:"phase"`
+	Windows  []string `json:"windows" yaml:"windows"`
+
+	// The gates of a combinator and, for "atLeast", how many of them must be
+	// "good".
+	Gates []GateSpec `json:"gates" yaml:"gates"`
+	K     int        `json:"k" yaml:"k"`
+}
+
+// combinators are the gate types that combine other gates.
+var combinators = map[string]bool{"and": true, "or": true, "xor": true, "not": true, "atLeast": true, "majority": true}
+
+// DataSpec describes a Data series and the gates that decide whether its
+// data is "good" or "bad". Fields that are not set default to the values of
+// NewDataConfig.
+type DataSpec struct {
+	DataConfig `yaml:",inline"`
+
+	// Gates that decide whether the data of this series is "good" or "bad".
+	Gates []GateSpec `json:"gates" yaml:"gates"`
+}
+
+// UnmarshalJSON decodes a DataSpec on top of the NewDataConfig defaults.
+// Like ParseScenarioSpec it rejects unknown fields, including those of its
+// gates.
+func (ds *DataSpec) UnmarshalJSON(b []byte) error {
+	type plain DataSpec
+	*ds = DataSpec{DataConfig: NewDataConfig("", 0)}
+
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.DisallowUnknownFields()
+	return dec.Decode((*plain)(ds))
+}
+
+// UnmarshalYAML decodes a DataSpec on top of the NewDataConfig defaults.
+// Like ParseScenarioSpec it rejects unknown fields, including those of its
+// gates.
+func (ds *DataSpec) UnmarshalYAML(value *yaml.Node) error {
+	type plain DataSpec
+	*ds = DataSpec{DataConfig: NewDataConfig("", 0)}
+
+	// Decoding a yaml.Node ignores KnownFields of the decoder so the keys
+	// are checked by hand
+	if err := knownYAMLFields(value, reflect.TypeOf(*ds)); err != nil {
+		return err
+	}
+
+	return value.Decode((*plain)(ds))
+}
+
+// knownYAMLFields returns an error naming the first key of a mapping in the
+// node, or in the mappings nested in it, that has no field in the struct t.
+func knownYAMLFields(node *yaml.Node, t reflect.Type) error {
+	switch t.Kind() {
+	case reflect.Ptr, reflect.Slice:
+		if node.Kind == yaml.SequenceNode && t.Kind() == reflect.Slice {
+			for _, n := range node.Content {
+				if err := knownYAMLFields(n, t.Elem()); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+		return knownYAMLFields(node, t.Elem())
+	case reflect.Struct:
+	default:
+		return nil
+	}
+
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
+
+	fields := map[string]reflect.Type{}
+	var collect func(t reflect.Type)
+	collect = func(t reflect.Type) {
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			name, opts, _ := strings.Cut(f.Tag.Get("yaml"), ",")
+			switch {
+			case name == "-":
+			case opts == "inline":
+				collect(f.Type)
+			case name == "":
+				fields[strings.ToLower(f.Name)] = f.Type
+			default:
+				fields[name] = f.Type
+			}
+		}
+	}
+	collect(t)
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		k, v := node.Content[i], node.Content[i+1]
+		ft, ok := fields[k.Value]
+		if !ok {
+			return fmt.Errorf("yaml: line %v: field %v not found in type %v", k.Line, k.Value, t)
+		}
+
+		if err := knownYAMLFields(v, ft); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Validate checks the whole scenario. It returns nil or a *ConfigError listing
+// every invalid field. Fields of gates and data series are prefixed with their
+// position, e.g. "Data[0].From".
+func (ss ScenarioSpec) Validate() error {
+	ce := &ConfigError{Kind: "scenario", ID: ss.Time.ID}
+
+	if err := ss.Time.Validate(); err != nil {
+		for _, fe := range err.(*ConfigError).Fields {
+			ce.add("Time."+fe.Field, fe.Value, fe.Reason)
+		}
+	}
+
+	ids := map[string]bool{}
+	checkID := func(field string, id string) {
+		if id != "" && ids[id] {
+			ce.add(field, id, "must be unique")
+		}
+		ids[id] = true
+	}
+
+	checkSeed := func(field string, seed int64) {
+		if ss.MasterSeed != nil && seed != 0 {
+			ce.add(field, seed, "cannot be set together with MasterSeed")
+		}
+	}
+
+	// The gates of combinators are validated by the combinator but their IDs
+	// and seeds are checked here
+	var checkNested func(prefix string, gates []GateSpec)
+	checkNested = func(prefix string, gates []GateSpec) {
+		for i, g := range gates {
+			field := prefix + "Gates[" + fmt.Sprintf("%v", i) + "]"
+			checkID(field+".ID", g.ID)
+			checkSeed(field+".Seed", g.Seed)
+			checkNested(field+".", g.Gates)
+		}
+	}
+
+	checkGates := func(prefix string, gates []GateSpec) {
+		for i, g := range gates {
+			field := prefix + "Gates[" + fmt.Sprintf("%v", i) + "]"
+			checkID(field+".ID", g.ID)
+			checkSeed(field+".Seed", g.Seed)
+			checkNested(field+".", g.Gates)
+			if err := g.Validate(); err != nil {
+				for _, fe := range err.(*ConfigError).Fields {
+					ce.add(field+"."+fe.Field, fe.Value, fe.Reason)
+				}
+			}
+		}
+	}
+
+	checkID("Time.ID", ss.Time.ID)
+	checkSeed("Time.Seed", ss.Time.Seed)
+	checkGates("", ss.Gates)
+
+	if len(ss.Data) == 0 {
+		ce.add("Data", len(ss.Data), "must have at least one series")
+	}
+
+	for i, d := range ss.Data {
+		prefix := "Data[" + fmt.Sprintf("%v", i) + "]."
+		checkID(prefix+"ID", d.ID)
+		checkSeed(prefix+"Seed", d.Seed)
+
+		cfg := ss.dataConfig(d)
+		if err := cfg.Validate(); err != nil {
+			for _, fe := range err.(*ConfigError).Fields {
+				ce.add(prefix+fe.Field, fe.Value, fe.Reason)
+			}
+		}
+
+		checkGates(prefix, d.Gates)
+	}
+
+	return ce.err()
+}
+
+// dataConfig returns the DataConfig of a series using the scenario's default
+// sample count if the series did not set one.
+func (ss ScenarioSpec) dataConfig(d DataSpec) DataConfig {
+	cfg := d.DataConfig
+	if cfg.Samples == 0 {
+		cfg.Samples = ss.Samples
+	}
+
+	return cfg
+}
+
+// Validate checks the gate. It returns nil or a *ConfigError listing every
+// invalid field.
+func (gs GateSpec) Validate() error {
+	ce := &ConfigError{Kind: "gate", ID: gs.ID}
+
+	if gs.ID == "" {
+		ce.add("ID", gs.ID, "cannot be blank")
+	}
+
+	if gs.Gates != nil && !combinators[gs.Type] {
+		ce.add("Gates", len(gs.Gates), "can only be set for a combinator")
+	}
+
+	switch gs.Type {
+	case "pattern":
+		if gs.Windows != nil {
+			if _, err := parseWindows(gs.Windows); err != nil {
+				ce.add("Windows", gs.Windows, err.Error())
+			}
+
+			break
+		}
+
+		cfg := PatternConfig{ID: gs.ID, Good: gs.Good, Bad: gs.Bad, Sequence: gs.Sequence, Phase: gs.Phase}
+		if err := cfg.Validate(); err != nil {
+			for _, fe := range err.(*ConfigError).Fields {
+				if fe.Field != "ID" {
+					ce.Fields = append(ce.Fields, fe)
+				}
+			}
+		}
+	case "random":
+		if gs.PctGood < 0 || gs.PctGood > 1 {
+			ce.add("PctGood", gs.PctGood, "must be between 0 and 1")
+		}
+	case "burst":
+		burstTransitions(ce, gs.PctGood, gs.MeanBurst)
+	case "and", "or", "xor", "not", "atLeast", "majority":
+		switch {
+		case gs.Type == "not" && len(gs.Gates) != 1:
+			ce.add("Gates", len(gs.Gates), "must be exactly one gate")
+		case len(gs.Gates) == 0:
+			ce.add("Gates", len(gs.Gates), "must have at least one gate")
+		}
+
+		if gs.Type == "atLeast" && (gs.K < 1 || gs.K > len(gs.Gates)) {
+			ce.add("K", gs.K, "must be between 1 and the number of gates")
+		}
+
+		for i, g := range gs.Gates {
+			if err := g.Validate(); err != nil {
+				for _, fe := range err.(*ConfigError).Fields {
+					ce.add("Gates["+fmt.Sprintf("%v", i)+"]."+fe.Field, fe.Value, fe.Reason)
+				}
+			}
+		}
+	default:
+		ce.add("Type", gs.Type, "must be 'pattern', 'random', 'burst' or a combinator")
+	}
+
+	return ce.err()
+}
+
+// deriveSeeds returns a copy of the spec with every seed derived from the
+// SeedSource.
+func (ss ScenarioSpec) deriveSeeds(src SeedSource) ScenarioSpec {
+	var deriveGates func(gates []GateSpec) []GateSpec
+	deriveGates = func(gates []GateSpec) []GateSpec {
+		if gates == nil {
+			return nil
+		}
+
+		out := make([]GateSpec, len(gates))
+		for i, g := range gates {
+			g.Seed = src.Seed(g.ID)
+			g.Gates = deriveGates(g.Gates)
+			out[i] = g
+		}
+		return out
+	}
+
+	ss.Time.Seed = src.Seed(ss.Time.ID)
+	ss.Gates = deriveGates(ss.Gates)
+
+	data := make([]DataSpec, len(ss.Data))
+	for i, d := range ss.Data {
+		d.Seed = src.Seed(d.ID)
+		d.Gates = deriveGates(d.Gates)
+		data[i] = d
+	}
+	ss.Data = data
+
+	return ss
+}
+
+// build creates the Pattern, Random, BurstRandom or Combinator described by
+// the spec. Patterns with windows follow the time of the scenario.
+func (gs GateSpec) build(ft *Time) (Gate, error) {
+	switch gs.Type {
+	case "pattern":
+		if gs.Windows != nil {
+			return NewTimePattern(gs.ID, ft, gs.Windows, gs.KeepStats)
+		}
+
+		return NewPatternFromConfig(PatternConfig{ID: gs.ID, Good: gs.Good, Bad: gs.Bad, Sequence: gs.Sequence, Phase: gs.Phase, KeepStats: gs.KeepStats})
+	case "random":
+		return NewRandom(gs.ID, gs.Seed, gs.PctGood, gs.KeepStats)
+	case "burst":
+		return NewBurstRandom(gs.ID, gs.Seed, gs.PctGood, gs.MeanBurst, gs.KeepStats)
+	}
+
+	if !combinators[gs.Type] {
+		return nil, errors.New("type of a fake gate with id '" + gs.ID + "' must be 'pattern', 'random', 'burst' or a combinator but was '" + gs.Type + "'")
+	}
+
+	if len(gs.Gates) == 0 {
+		return nil, errors.New("fake combinator with id '" + gs.ID + "' must have at least one gate")
+	}
+
+	gates, err := buildGates(gs.Gates, ft)
+	if err != nil {
+		return nil, err
+	}
+
+	var fc *Combinator
+	switch gs.Type {
+	case "and":
+		fc = And(gates[0], gates[1:]...)
+	case "or":
+		fc = Or(gates[0], gates[1:]...)
+	case "xor":
+		fc = Xor(gates[0], gates[1:]...)
+	case "not":
+		if len(gates) != 1 {
+			return nil, errors.New("fake combinator 'not' with id '" + gs.ID + "' must have exactly one gate")
+		}
+
+		fc = Not(gates[0])
+	case "atLeast":
+		fc = AtLeast(gs.K, gates...)
+	default:
+		fc = Majority(gates...)
+	}
+
+	fc.Named(gs.ID)
+	if gs.KeepStats {
+		fc.WithStats()
+	}
+
+	return fc, nil
+}
+
+// Scenario drives a Time, its sample gates and all Data series in lock-step.
+// Every call to Next() advances all of them by one sample.
+type Scenario struct {
+	Time   *Time
+	Gates  []Gate
+	Series []*Series
+	i      int64
+}
+
+// Series is a named Data together with the gates that decide whether its data
+// is "good" or "bad".
+type Series struct {
+	ID    string
+	Data  *Data
+	Gates []Gate
+}
+
+// Good returns whether every gate of the series is "good".
+func (s *Series) Good() bool {
+	return AllGood(s.Gates...)
+}
+
+// Row is a single sample of a Scenario.
+type Row struct {
+	// Position of the row, starting at 0.
+	Index int64 `json:"index"`
+
+	// The time of the sample.
+	Time time.Time `json:"time"`
+
+	// Whether every sample gate is "good".
+	Good bool `json:"good"`
+
+	// The value of every series in the order they were defined.
+	Fields []Field `json:"fields"`
+}
+
+// Field is the value of a single series in a Row.
+type Field struct {
+	// The ID of the series.
+	ID string `json:"id"`
+
+	// The current value of the series.
+	Value float64 `json:"value"`
+
+	// Whether every data gate of the series is "good".
+	Good bool `json:"good"`
+}
+
+// Next advances every generator of the scenario by one sample.
+func (sc *Scenario) Next() {
+	sc.Time.Next()
+
+	for _, g := range sc.Gates {
+		g.Next()
+	}
+
+	for _, s := range sc.Series {
+		s.Data.Next()
+		for _, g := range s.Gates {
+			g.Next()
+		}
+	}
+
+	sc.i++
+}
+
+// Index returns the position of the current row, the first row being 0.
+func (sc *Scenario) Index() int64 {
+	return sc.i
+}
+
+// Row returns the current row.
+func (sc *Scenario) Row() Row {
+	r := Row{
+		Index:  sc.i,
+		Time:   sc.Time.Time(),
+		Good:   AllGood(sc.Gates...),
+		Fields: make([]Field, len(sc.Series)),
+	}
+
+	for i, s := range sc.Series {
+		r.Fields[i] = Field{ID: s.ID, Value: s.Data.Float(), Good: s.Good()}
+	}
+
+	return r
+}
+
+// Rows returns the next count of rows as a Row array.
+func (sc *Scenario) Rows(count int) []Row {
+	out := make([]Row, count)
+	sc.Fill(out)
+	return out
+}
+
+// Step returns the current row and generates the next one.
+func (sc *Scenario) Step() Row {
+	out := sc.Row()
+	sc.Next()
+	return out
+}
+
+// Fill writes the next len(dst) rows into dst and returns how many were
+// written.
+func (sc *Scenario) Fill(dst []Row) int {
+	for i := range dst {
+		dst[i] = sc.Row()
+		sc.Next()
+	}
+
+	return len(dst)
+}
+
+// Val returns the current row as an interface{}.
+func (sc *Scenario) Val() interface{} {
+	return sc.Row()
+}
+
+// Vals returns the next count of rows as an interface{} array.
+func (sc *Scenario) Vals(count int) []interface{} {
+	return makeValues(sc, count)
+}
+
+// JSONStats retrieves the current stats of every generator as a JSON object
+// keyed by ID.
+func (sc *Scenario) JSONStats() string {
+	stats := map[string]json.RawMessage{sc.Time.ID(): json.RawMessage(sc.Time.JSONStats())}
+
+	for _, g := range sc.Gates {
+		stats[g.ID()] = json.RawMessage(g.JSONStats())
+	}
+
+	for _, s := range sc.Series {
+		stats[s.ID] = json.RawMessage(s.Data.JSONStats())
+		for _, g := range s.Gates {
+			stats[g.ID()] = json.RawMessage(g.JSONStats())
+		}
+	}
+
+	out, _ := json.Marshal(stats)
+	return string(out)
+}
+
+// scenarioState is the runtime state of a Scenario saved by Snapshot().
+type scenarioState struct {
+	I          int64                      `json:"i"`
+	Generators map[string]json.RawMessage `json:"generators"`
+}
+
+// snapshotters returns every generator of the scenario keyed by ID.
+func (sc *Scenario) snapshotters() map[string]Snapshotter {
+	out := map[string]Snapshotter{sc.Time.ID(): sc.Time}
+
+	for _, g := range sc.Gates {
+		out[g.ID()] = g
+	}
+
+	for _, s := range sc.Series {
+		out[s.ID] = s.Data
+		for _, g := range s.Gates {
+			out[g.ID()] = g
+		}
+	}
+
+	return out
+}
+
+// Snapshot saves the runtime state of every generator of the scenario so it
+// can be restored later with Restore().
+func (sc *Scenario) Snapshot() ([]byte, error) {
+	st := scenarioState{I: sc.i, Generators: map[string]json.RawMessage{}}
+
+	for id, g := range sc.snapshotters() {
+		b, err := g.Snapshot()
+		if err != nil {
+			return nil, err
+		}
+		st.Generators[id] = b
+	}
+
+	return json.Marshal(st)
+}
+
+// Restore restores the runtime state saved by Snapshot(). The scenario must
+// have been created from the same ScenarioSpec as the one that was saved.
+func (sc *Scenario) Restore(b []byte) error {
+	var st scenarioState
+	if err := json.Unmarshal(b, &st); err != nil {
+		return err
+	}
+
+	for id, g := range sc.snapshotters() {
+		gb, ok := st.Generators[id]
+		if !ok {
+			return errors.New("snapshot of a fake scenario is missing generator with id '" + id + "'")
+		}
+
+		if err := g.Restore(gb); err != nil {
+			return err
+		}
+	}
+
+	sc.i = st.I
+	return nil
+}
+
+// NewScenario validates a ScenarioSpec and creates all of its generators.
+func NewScenario(spec ScenarioSpec) (*Scenario, error) {
+	if err := spec.Validate(); err != nil {
+		return nil, err
+	}
+
+	if spec.MasterSeed != nil {
+		spec = spec.deriveSeeds(NewSeedSource(*spec.MasterSeed))
+	}
+
+	t, err := NewTimeFromConfig(spec.Time)
+	if err != nil {
+		return nil, err
+	}
+
+	sc := &Scenario{Time: t}
+
+	if sc.Gates, err = buildGates(spec.Gates, t); err != nil {
+		return nil, err
+	}
+
+	for _, ds := range spec.Data {
+		d, err := NewDataFromConfig(spec.dataConfig(ds))
+		if err != nil {
+			return nil, err
+		}
+
+		gates, err := buildGates(ds.Gates, t)
+		if err != nil {
+			return nil, err
+		}
+
+		sc.Series = append(sc.Series, &Series{ID: ds.ID, Data: d, Gates: gates})
+	}
+
+	return sc, nil
+}
+
+// ParseScenarioSpec reads a ScenarioSpec in the given format ("json" or
+// "yaml").
+func ParseScenarioSpec(r io.Reader, format string) (ScenarioSpec, error) {
+	var spec ScenarioSpec
+
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return spec, err
+	}
+
+	switch strings.ToLower(format) {
+	case "json":
+		dec := json.NewDecoder(bytes.NewReader(b))
+		dec.DisallowUnknownFields()
+		err = dec.Decode(&spec)
+	case "yaml", "yml":
+		dec := yaml.NewDecoder(bytes.NewReader(b))
+		dec.KnownFields(true)
+		err = dec.Decode(&spec)
+	default:
+		err = errors.New("format of a fake scenario must be 'json' or 'yaml' but was '" + format + "'")
+	}
+
+	return spec, err
+}
+
+// LoadScenario reads a ScenarioSpec in the given format ("json" or "yaml")
+// and creates a Scenario from it.
+func LoadScenario(r io.Reader, format string) (*Scenario, error) {
+	spec, err := ParseScenarioSpec(r, format)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewScenario(spec)
+}
+
+// LoadScenarioFile reads a ScenarioSpec from a file and creates a Scenario
+// from it. The format is determined by the file extension (".json", ".yaml"
+// or ".yml").
+func LoadScenarioFile(path string) (*Scenario, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return LoadScenario(bytes.NewReader(b), strings.TrimPrefix(filepath.Ext(path), "."))
+}
+
+func buildGates(specs []GateSpec, ft *Time) ([]Gate, error) {
+	gates := make([]Gate, 0, len(specs))
+
+	for _, gs := range specs {
+		g, err := gs.build(ft)
+		if err != nil {
+			return nil, err
+		}
+		gates = append(gates, g)
+	}
+
+	return gates, nil
+}
@@ -0,0 +1,165 @@
+package fake
+
+import (
+	"fmt"
+	"strings"
+)
+
+func ExampleLoadScenarioFile() {
+	sc, _ := LoadScenarioFile("testdata/scenario.yaml")
+
+	for _, r := range sc.Rows(7) {
+		if !r.Good {
+			fmt.Printf("%v: bad sample\n", r.Time.Unix())
+			continue
+		}
+
+		fmt.Printf("%v:", r.Time.Unix())
+		for _, f := range r.Fields {
+			fmt.Printf(" %v=%.2f(%v)", f.ID, f.Value, f.Good)
+		}
+		fmt.Println()
+	}
+	// Output:
+	// 1581033600: cpu=-57.28(true) memory=50.00(true)
+	// 1581037200: cpu=-63.07(true) memory=51.00(true)
+	// 1581040800: cpu=-63.88(true) memory=52.00(true)
+	// 1581044400: bad sample
+	// 1581048000: cpu=-63.59(true) memory=54.00(true)
+	// 1581051600: cpu=-64.64(true) memory=55.00(true)
+	// 1581055200: cpu=-59.01(true) memory=56.00(false)
+}
+
+func ExampleLoadScenario() {
+	spec := `{
+		"samples": 10,
+		"time": {"id": "ts", "start": "2020-02-07T00:00:00Z", "increment": 1000},
+		"gates": [{"id": "ts", "type": "coin"}],
+		"data": [{"id": "cpu", "from": 100, "to": 0}]
+	}`
+
+	_, err := LoadScenario(strings.NewReader(spec), "json")
+	fmt.Println(err)
+	// Output: invalid fake scenario with id 'ts': Gates[0].ID must be unique but was 'ts'; Gates[0].Type must be 'pattern', 'random', 'burst' or a combinator but was 'coin'; Data[0].From cannot be greater than To (0) but was '100'
+}
+
+func ExampleLoadScenario_patterns() {
+	spec := `
+samples: 8
+time: {id: ts, start: "2020-02-07T01:00:00Z", increment: 900000}
+gates:
+  - {id: maintenance, type: pattern, sequence: "G*3 (B G)*2", phase: 1}
+  - {id: backup, type: pattern, windows: ["02:00-02:30"]}
+data:
+  - {id: cpu, slope: 1}
+`
+
+	sc, _ := LoadScenario(strings.NewReader(spec), "yaml")
+	for _, r := range sc.Rows(8) {
+		fmt.Println(r.Time.Format("15:04"), r.Good)
+	}
+	// Output:
+	// 01:00 true
+	// 01:15 true
+	// 01:30 false
+	// 01:45 true
+	// 02:00 false
+	// 02:15 false
+	// 02:30 true
+	// 02:45 true
+}
+
+func ExampleLoadScenario_combinators() {
+	spec := `
+samples: 6
+time: {id: ts, start: "2020-02-07T01:00:00Z", increment: 900000}
+gates:
+  - id: outage
+    type: and
+    keepStats: true
+    gates:
+      - {id: network, type: pattern, good: 4, bad: 2}
+      - id: collectors
+        type: atLeast
+        k: 1
+        gates:
+          - {id: primary, type: pattern, good: 2, bad: 1}
+          - {id: secondary, type: pattern, sequence: "G B"}
+data:
+  - {id: cpu, slope: 1}
+`
+
+	sc, _ := LoadScenario(strings.NewReader(spec), "yaml")
+	for _, r := range sc.Rows(6) {
+		fmt.Println(r.Time.Format("15:04"), r.Good)
+	}
+	outage := sc.Gates[0].(*Combinator)
+	fmt.Println(outage.Stats.CBadBy, outage.Gates()[1].(*Combinator).Stats.CTotal)
+
+	_, err := LoadScenario(strings.NewReader(`
+samples: 6
+time: {id: ts, start: "2020-02-07T01:00:00Z", increment: 900000}
+gates:
+  - {id: lost, type: not, gates: [{id: ts, type: random, pctGood: 2}, {id: b, type: pattern, good: 1}]}
+data:
+  - {id: cpu}
+`), "yaml")
+	fmt.Println(err)
+	// Output:
+	// 01:00 true
+	// 01:15 true
+	// 01:30 true
+	// 01:45 true
+	// 02:00 false
+	// 02:15 false
+	// map[collectors:1 network:2] 0
+	// invalid fake scenario with id 'ts': Gates[0].Gates[0].ID must be unique but was 'ts'; Gates[0].Gates must be exactly one gate but was '2'; Gates[0].Gates[0].PctGood must be between 0 and 1 but was '2'
+}
+
+func ExampleScenario_Snapshot() {
+	sc1, _ := LoadScenarioFile("testdata/scenario.yaml")
+	sc1.Rows(3)
+	snapshot, _ := sc1.Snapshot()
+
+	sc2, _ := LoadScenarioFile("testdata/scenario.yaml")
+	sc2.Restore(snapshot)
+
+	for _, r := range sc2.Rows(2) {
+		fmt.Printf("%v %v %.2f %.2f\n", r.Index, r.Good, r.Fields[0].Value, r.Fields[1].Value)
+	}
+	// Output:
+	// 3 false -63.76 53.00
+	// 4 true -63.59 54.00
+}
+
+// Misspelled fields are rejected everywhere, also inside data series.
+func ExampleParseScenarioSpec_json() {
+	_, err := ParseScenarioSpec(strings.NewReader(`{"data": [{"id": "cpu", "form": 5}]}`), "json")
+	fmt.Println(err)
+
+	_, err = ParseScenarioSpec(strings.NewReader(`{"data": [{"id": "cpu", "gates": [{"id": "p", "type": "pattern", "god": 1}]}]}`), "json")
+	fmt.Println(err)
+	// Output:
+	// json: unknown field "form"
+	// json: unknown field "god"
+}
+
+func ExampleParseScenarioSpec_yaml() {
+	_, err := ParseScenarioSpec(strings.NewReader(`
+data:
+  - id: cpu
+    form: 5
+`), "yaml")
+	fmt.Println(err)
+
+	_, err = ParseScenarioSpec(strings.NewReader(`
+data:
+  - id: cpu
+    gates:
+      - {id: p, type: pattern, god: 1}
+`), "yaml")
+	fmt.Println(err)
+	// Output:
+	// yaml: line 4: field form not found in type fake.DataSpec
+	// yaml: line 5: field god not found in type fake.GateSpec
+}
@@ -0,0 +1,83 @@
+package fake
+
+import (
+	"encoding/binary"
+	"hash/fnv"
+)
+
+// SeedSource derives a distinct but stable seed for every generator from a
+// single master seed and the generator's ID path, e.g. "host-17/cpu". As the
+// seed of a generator only depends on the master seed and its own path,
+// adding or removing generators never changes the values of any other.
+type SeedSource struct {
+	master int64
+	path   []string
+}
+
+// NewSeedSource creates a SeedSource from a master seed.
+func NewSeedSource(master int64) SeedSource {
+	return SeedSource{master: master}
+}
+
+// Sub returns a SeedSource whose path is extended by the given names, e.g.
+// NewSeedSource(1).Sub("host-17").Seed("cpu") derives the seed of
+// "host-17/cpu".
+func (ss SeedSource) Sub(names ...string) SeedSource {
+	path := make([]string, 0, len(ss.path)+len(names))
+	path = append(path, ss.path...)
+	path = append(path, names...)
+	return SeedSource{master: ss.master, path: path}
+}
+
+// Seed derives the seed for the given ID below the path of the SeedSource.
+// Derived seeds are never negative as negative seeds mean "seed from the
+// current time".
+func (ss SeedSource) Seed(id string) int64 {
+	h := fnv.New64a()
+
+	var master [8]byte
+	binary.LittleEndian.PutUint64(master[:], uint64(ss.master))
+	h.Write(master[:])
+
+	// Separate names so that "ab", "c" and "a", "bc" derive different seeds
+	for _, name := range append(ss.path, id) {
+		h.Write([]byte(name))
+		h.Write([]byte{0})
+	}
+
+	// FNV alone does not spread similar IDs (e.g. "host-1" and "host-2")
+	// far apart so finish with the SplitMix64 mixer
+	z := h.Sum64()
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+	z = z ^ (z >> 31)
+
+	return int64(z >> 1)
+}
+
+// NewRandom creates a new Random (see NewRandom) seeded from the SeedSource
+// and its id.
+func (ss SeedSource) NewRandom(id string, pctGood float64, keepStats bool) (*Random, error) {
+	return NewRandom(id, ss.Seed(id), pctGood, keepStats)
+}
+
+// NewTime creates a new Time from a TimeConfig whose seed is replaced by one
+// derived from the SeedSource and the config's ID.
+func (ss SeedSource) NewTime(cfg TimeConfig) (*Time, error) {
+	cfg.Seed = ss.Seed(cfg.ID)
+	return NewTimeFromConfig(cfg)
+}
+
+// NewData creates a new Data from a DataConfig whose seed is replaced by one
+// derived from the SeedSource and the config's ID.
+func (ss SeedSource) NewData(cfg DataConfig) (*Data, error) {
+	cfg.Seed = ss.Seed(cfg.ID)
+	return NewDataFromConfig(cfg)
+}
+
+// WithSeedSource derives the seed from a SeedSource and the ID of the config.
+func WithSeedSource(ss SeedSource) DataOption {
+	return func(cfg *DataConfig) {
+		cfg.Seed = ss.Seed(cfg.ID)
+	}
+}
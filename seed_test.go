@@ -0,0 +1,46 @@
+package fake
+
+import (
+	"fmt"
+	"strings"
+)
+
+func ExampleSeedSource() {
+	hosts := NewSeedSource(42).Sub("host-17")
+	fmt.Println(hosts.Seed("cpu") == NewSeedSource(42).Sub("host-17").Seed("cpu"))
+	fmt.Println(hosts.Seed("cpu") == hosts.Seed("memory"))
+
+	cpu, _ := hosts.NewData(NewDataConfig("cpu", 10, WithRandom(0, 0.5)))
+	again, _ := NewDataFromConfig(NewDataConfig("cpu", 10, WithRandom(0, 0.5), WithSeedSource(hosts)))
+	fmt.Println(cpu.Float() == again.Float())
+	// Output:
+	// true
+	// false
+	// true
+}
+
+func ExampleScenarioSpec_masterSeed() {
+	spec := `
+samples: 10
+masterSeed: 7
+time: {id: ts, start: 2020-02-07T00:00:00Z, increment: 1000}
+data:
+  - {id: cpu, useRandom: true}
+  - {id: memory, useRandom: true}
+`
+	more := spec + "  - {id: disk, useRandom: true}\n"
+
+	sc1, _ := LoadScenario(strings.NewReader(spec), "yaml")
+	sc2, _ := LoadScenario(strings.NewReader(more), "yaml")
+
+	// Adding "disk" does not change "cpu" or "memory"
+	r1, r2 := sc1.Rows(5), sc2.Rows(5)
+	same := true
+	for i := range r1 {
+		for j := range r1[i].Fields {
+			same = same && r1[i].Fields[j] == r2[i].Fields[j]
+		}
+	}
+	fmt.Println(same, len(r2[0].Fields))
+	// Output: true 3
+}
@@ -0,0 +1,230 @@
+// Package statsdout sends fake time series as StatsD metrics.
+//
+// An Encoder takes a set of metrics and writes one line per metric and
+// sample, packed into packets small enough for UDP, either to any io.Writer
+// or straight to a StatsD daemon:
+//
+//  conn, err := net.Dial("udp", "localhost:8125")
+//  e := statsdout.NewEncoder(conn,
+//      statsdout.Metric{Name: "host-1.cpu", Kind: statsdout.Gauge, Value: fakeData1},
+//      statsdout.Metric{Name: "host-1.requests", Kind: statsdout.Counter, Value: fakeData2, Sampler: fakeRandom})
+//  err = e.Encode(1000)
+//
+// which may look as follows:
+//
+//  host-1.cpu:23.5|g
+//  host-1.requests:12|c|@0.1
+//  ...etc...
+//
+// StatsD has no timestamps: the daemon uses the time a packet arrives, so use
+// a fake.Pacer to send samples in real time. A metric whose gate is "bad" (or
+// any sample while a gate of the Encoder is "bad") is left out.
+package statsdout
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	fake "github.com/powerpu/go-fake-ts"
+)
+
+// Kind is the StatsD type of a metric.
+type Kind int
+
+const (
+	// Gauge sets the metric to the value of every sample.
+	Gauge Kind = iota
+
+	// Counter adds the value of every sample to the metric.
+	Counter
+
+	// Timer records the value of every sample as a duration in milliseconds.
+	Timer
+)
+
+// Metric is a single metric of an Encoder.
+type Metric struct {
+	// The dot separated name of the metric, e.g. "host-1.cpu".
+	Name string
+
+	// The type of the metric. Defaults to Gauge.
+	Kind Kind
+
+	// DogStatsD style tags appended as "|#name:value" sorted by name.
+	// Optional.
+	Tags map[string]string
+
+	// The value of the metric, usually a Data but any Value returning a
+	// float64 will do.
+	Value fake.Value
+
+	// Decide whether the data of the metric is "good". Optional.
+	Gates []fake.Gate
+
+	// Samples the metric: a sample is only sent while the Random is "good"
+	// and carries its PctGood() as the sample rate so StatsD scales it back
+	// up. Optional.
+	Sampler *fake.Random
+}
+
+// Encoder writes a set of metrics as StatsD lines. Every call to Encode
+// advances the gates and all metrics in lock-step so none of them may appear
+// twice or be used elsewhere while encoding.
+//
+// An Encoder must be created with NewEncoder. The zero value has nowhere to
+// write to.
+type Encoder struct {
+	// Decide whether a sample is "good" for all metrics. Optional.
+	Gates []fake.Gate
+
+	// The metrics to write.
+	Metrics []Metric
+
+	// The largest packet written to the underlying io.Writer at a time.
+	// Lines are never split so a single longer line is written on its own.
+	// Defaults to 1432 bytes, which fits the MTU of most networks.
+	MaxPacketSize int
+
+	w io.Writer
+}
+
+// NewEncoder creates an Encoder writing to w with all defaults set.
+func NewEncoder(w io.Writer, metrics ...Metric) *Encoder {
+	return &Encoder{Metrics: metrics, MaxPacketSize: 1432, w: w}
+}
+
+// Encode writes the lines of the next count samples.
+func (e *Encoder) Encode(count int) error {
+	if e.w == nil {
+		return errors.New("statsdout: an Encoder must be created with NewEncoder")
+	}
+
+	maxPacketSize := e.MaxPacketSize
+	if maxPacketSize <= 0 {
+		maxPacketSize = 1432
+	}
+
+	suffixes := make([]string, len(e.Metrics))
+	for i, m := range e.Metrics {
+		suffixes[i] = suffix(m)
+	}
+
+	var packet bytes.Buffer
+	write := func(line string) error {
+		// Lines are separated by newlines within a packet
+		if packet.Len() > 0 && packet.Len()+1+len(line) > maxPacketSize {
+			if _, err := e.w.Write(packet.Bytes()); err != nil {
+				return err
+			}
+
+			packet.Reset()
+		}
+
+		if packet.Len() > 0 {
+			packet.WriteByte('\n')
+		}
+
+		packet.WriteString(line)
+		return nil
+	}
+
+	for n := 0; n < count; n++ {
+		if fake.AllGood(e.Gates...) {
+			for i, m := range e.Metrics {
+				if !fake.AllGood(m.Gates...) || (m.Sampler != nil && m.Sampler.Bad()) {
+					continue
+				}
+
+				v, ok := m.Value.Val().(float64)
+				if !ok || math.IsNaN(v) || math.IsInf(v, 0) {
+					continue
+				}
+
+				name := sanitize(m.Name)
+
+				// A signed gauge changes the gauge by the value instead of
+				// setting it, so negative gauges are reset to 0 first
+				if m.Kind == Gauge && v < 0 {
+					if err := write(name + ":0" + suffixes[i]); err != nil {
+						return err
+					}
+				}
+
+				if err := write(name + ":" + strconv.FormatFloat(v, 'f', -1, 64) + suffixes[i]); err != nil {
+					return err
+				}
+			}
+		}
+
+		e.next()
+	}
+
+	if packet.Len() > 0 {
+		_, err := e.w.Write(packet.Bytes())
+		return err
+	}
+
+	return nil
+}
+
+// next advances every generator by one sample.
+func (e *Encoder) next() {
+	for _, g := range e.Gates {
+		g.Next()
+	}
+
+	for _, m := range e.Metrics {
+		m.Value.Next()
+		for _, g := range m.Gates {
+			g.Next()
+		}
+
+		if m.Sampler != nil {
+			m.Sampler.Next()
+		}
+	}
+}
+
+// suffix returns everything of a line after the value.
+func suffix(m Metric) string {
+	s := "|g"
+	switch m.Kind {
+	case Counter:
+		s = "|c"
+	case Timer:
+		s = "|ms"
+	}
+
+	if m.Sampler != nil {
+		s += "|@" + strconv.FormatFloat(m.Sampler.PctGood(), 'f', -1, 64)
+	}
+
+	if len(m.Tags) > 0 {
+		names := make([]string, 0, len(m.Tags))
+		for name := range m.Tags {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		tags := make([]string, len(names))
+		for i, name := range names {
+			tags[i] = sanitize(name) + ":" + sanitize(m.Tags[name])
+		}
+
+		s += "|#" + strings.Join(tags, ",")
+	}
+
+	return s
+}
+
+// Colons, pipes, commas and newlines would break a line apart
+var sanitizer = strings.NewReplacer(":", "_", "|", "_", ",", "_", "@", "_", "#", "_", "\n", "_")
+
+func sanitize(s string) string {
+	return sanitizer.Replace(s)
+}
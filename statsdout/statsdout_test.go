@@ -0,0 +1,74 @@
+package statsdout
+
+import (
+	"fmt"
+	"net"
+	"os"
+
+	fake "github.com/powerpu/go-fake-ts"
+)
+
+func ExampleEncoder() {
+	temperature, _ := fake.NewDataFromConfig(fake.NewDataConfig("temperature", 5, fake.WithRange(-10, 10), fake.WithSlope(-5)))
+	reqs, _ := fake.NewDataFromConfig(fake.NewDataConfig("reqs", 5, fake.WithRange(10, 20)))
+	latency, _ := fake.NewDataFromConfig(fake.NewDataConfig("latency", 5, fake.WithRange(100, 200)))
+	fr, _ := fake.NewRandom("sampler", 4, 0.5, false)
+
+	e := NewEncoder(os.Stdout,
+		Metric{Name: "host-1.temperature", Value: temperature},
+		Metric{Name: "host-1.requests", Kind: Counter, Value: reqs, Sampler: fr},
+		Metric{Name: "host-1.latency", Kind: Timer, Tags: map[string]string{"path": "/api"}, Value: latency})
+	e.Encode(5)
+	// Output:
+	// host-1.temperature:0|g
+	// host-1.requests:15|c|@0.5
+	// host-1.latency:150|ms|#path:/api
+	// host-1.temperature:0|g
+	// host-1.temperature:-5|g
+	// host-1.requests:15|c|@0.5
+	// host-1.latency:150|ms|#path:/api
+	// host-1.temperature:0|g
+	// host-1.temperature:-10|g
+	// host-1.requests:15|c|@0.5
+	// host-1.latency:150|ms|#path:/api
+	// host-1.temperature:0|g
+	// host-1.temperature:-15|g
+	// host-1.latency:150|ms|#path:/api
+	// host-1.temperature:0|g
+	// host-1.temperature:-20|g
+	// host-1.requests:15|c|@0.5
+	// host-1.latency:150|ms|#path:/api
+}
+
+func ExampleEncoder_udp() {
+	// A local stand-in for a StatsD daemon
+	conn, _ := net.ListenPacket("udp", "127.0.0.1:0")
+	defer conn.Close()
+
+	cpu, _ := fake.NewDataFromConfig(fake.NewDataConfig("cpu", 10, fake.WithSlope(1)))
+
+	client, _ := net.Dial("udp", conn.LocalAddr().String())
+	defer client.Close()
+
+	e := NewEncoder(client, Metric{Name: "host-1.cpu", Value: cpu})
+	e.MaxPacketSize = 40
+	e.Encode(10)
+
+	// Lines are packed into packets of up to 40 bytes
+	buf := make([]byte, 1500)
+	for i := 0; i < 4; i++ {
+		n, _, _ := conn.ReadFrom(buf)
+		fmt.Printf("%q\n", buf[:n])
+	}
+	// Output:
+	// "host-1.cpu:50|g\nhost-1.cpu:51|g"
+	// "host-1.cpu:52|g\nhost-1.cpu:53|g"
+	// "host-1.cpu:54|g\nhost-1.cpu:55|g"
+	// "host-1.cpu:56|g\nhost-1.cpu:57|g"
+}
+
+func ExampleEncoder_zero() {
+	var e Encoder
+	fmt.Println(e.Encode(1))
+	// Output: statsdout: an Encoder must be created with NewEncoder
+}
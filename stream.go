@@ -0,0 +1,141 @@
+package fake
+
+import (
+	"context"
+	"iter"
+	"time"
+)
+
+// Stream sends the values of a Generator on a channel until the context is
+// done or limit values have been sent (a limit of 0 or less means no limit).
+// The channel is closed when done. Values are taken the same way as Fill so
+// streaming can be mixed with the typed slice helpers (Floats, Times and
+// Values). When the context is done the value that was about to be sent is
+// dropped.
+func Stream[T any](ctx context.Context, g Generator[T], limit int64) <-chan T {
+	out := make(chan T)
+
+	go func() {
+		defer close(out)
+
+		for i := int64(0); limit <= 0 || i < limit; i++ {
+			select {
+			case out <- g.Step():
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return out
+}
+
+// Seq returns an iterator over the values of a Generator that stops after
+// limit values (a limit of 0 or less means no limit). Values are taken the
+// same way as Fill so iterating can be mixed with the typed slice helpers.
+func Seq[T any](g Generator[T], limit int64) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for i := int64(0); limit <= 0 || i < limit; i++ {
+			if !yield(g.Step()) {
+				return
+			}
+		}
+	}
+}
+
+// Seq2 is like Seq but also yields the index of every value. Generators with
+// an Index() method, like every generator of this package, yield their own
+// index of the value (the first sample being 0), which keeps counting across
+// iterations and after SeekTo. Other generators yield the position within
+// the iteration, starting at 0.
+func Seq2[T any](g Generator[T], limit int64) iter.Seq2[int64, T] {
+	return func(yield func(int64, T) bool) {
+		ix, indexed := g.(interface{ Index() int64 })
+
+		for i := int64(0); limit <= 0 || i < limit; i++ {
+			index := i
+			if indexed {
+				index = ix.Index()
+			}
+
+			if !yield(index, g.Step()) {
+				return
+			}
+		}
+	}
+}
+
+// Stream sends the pattern values on a channel. See Stream.
+func (fp *Pattern) Stream(ctx context.Context, limit int64) <-chan bool {
+	return Stream[bool](ctx, fp, limit)
+}
+
+// Seq returns an iterator over the pattern values. See Seq.
+func (fp *Pattern) Seq(limit int64) iter.Seq[bool] {
+	return Seq[bool](fp, limit)
+}
+
+// Seq2 returns an iterator over the pattern values and their index. See Seq2.
+func (fp *Pattern) Seq2(limit int64) iter.Seq2[int64, bool] {
+	return Seq2[bool](fp, limit)
+}
+
+// Stream sends the random values on a channel. See Stream.
+func (fr *Random) Stream(ctx context.Context, limit int64) <-chan bool {
+	return Stream[bool](ctx, fr, limit)
+}
+
+// Seq returns an iterator over the random values. See Seq.
+func (fr *Random) Seq(limit int64) iter.Seq[bool] {
+	return Seq[bool](fr, limit)
+}
+
+// Seq2 returns an iterator over the random values and their index. See Seq2.
+func (fr *Random) Seq2(limit int64) iter.Seq2[int64, bool] {
+	return Seq2[bool](fr, limit)
+}
+
+// Stream sends the time values on a channel. See Stream.
+func (ft *Time) Stream(ctx context.Context, limit int64) <-chan time.Time {
+	return Stream[time.Time](ctx, ft, limit)
+}
+
+// Seq returns an iterator over the time values. See Seq.
+func (ft *Time) Seq(limit int64) iter.Seq[time.Time] {
+	return Seq[time.Time](ft, limit)
+}
+
+// Seq2 returns an iterator over the time values and their index. See Seq2.
+func (ft *Time) Seq2(limit int64) iter.Seq2[int64, time.Time] {
+	return Seq2[time.Time](ft, limit)
+}
+
+// Stream sends the rows on a channel. See Stream.
+func (sc *Scenario) Stream(ctx context.Context, limit int64) <-chan Row {
+	return Stream[Row](ctx, sc, limit)
+}
+
+// Seq returns an iterator over the rows. See Seq.
+func (sc *Scenario) Seq(limit int64) iter.Seq[Row] {
+	return Seq[Row](sc, limit)
+}
+
+// Seq2 returns an iterator over the rows and their index. See Seq2.
+func (sc *Scenario) Seq2(limit int64) iter.Seq2[int64, Row] {
+	return Seq2[Row](sc, limit)
+}
+
+// Stream sends the data values on a channel. See Stream.
+func (fd *Data) Stream(ctx context.Context, limit int64) <-chan float64 {
+	return Stream[float64](ctx, fd, limit)
+}
+
+// Seq returns an iterator over the data values. See Seq.
+func (fd *Data) Seq(limit int64) iter.Seq[float64] {
+	return Seq[float64](fd, limit)
+}
+
+// Seq2 returns an iterator over the data values and their index. See Seq2.
+func (fd *Data) Seq2(limit int64) iter.Seq2[int64, float64] {
+	return Seq2[float64](fd, limit)
+}
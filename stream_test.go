@@ -0,0 +1,99 @@
+package fake
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+func ExampleData_Seq() {
+	fd, _ := NewDataFromConfig(NewDataConfig("d1", 10, WithRange(50, 100), WithRandom(1, 0.5)))
+
+	for v := range fd.Seq(3) {
+		fmt.Printf("%v ", v)
+	}
+
+	// Iterating continues where the last one stopped
+	fmt.Printf("%v\n", fd.Floats(2))
+	// Output: -57.280531906981736 -63.071999277046245 -63.88021576640003 [-63.764429475766235 -63.59492175044973]
+}
+
+func ExamplePattern_Seq2() {
+	fp, _ := NewPattern("fakePattern7", 2, 1, false)
+
+	for i, good := range fp.Seq2(0) {
+		if !good {
+			fmt.Printf("first bad sample at %v\n", i)
+			break
+		}
+	}
+	// Output: first bad sample at 2
+}
+
+func ExampleRandom_Stream() {
+	fr, _ := NewRandom("fakeRandom6", 4, 0.5, false)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	for good := range fr.Stream(ctx, 10) {
+		fmt.Printf("%v ", good)
+	}
+	// Output: true true true false true true false false false false
+}
+
+// The index is the index of the value, not the position in the iteration.
+func ExampleData_Seq2() {
+	fd, _ := NewDataFromConfig(NewDataConfig("d1", 100, WithRange(0, 0), WithSlope(1)))
+	fd.SeekTo(10)
+
+	for i, v := range fd.Seq2(2) {
+		fmt.Println(i, v)
+	}
+
+	for i, v := range fd.Seq2(2) {
+		fmt.Println(i, v)
+	}
+	// Output:
+	// 10 10
+	// 11 11
+	// 12 12
+	// 13 13
+}
+
+func ExampleScenario_Seq2() {
+	sc, _ := LoadScenarioFile("testdata/scenario.yaml")
+	sc.Rows(3)
+
+	for i, r := range sc.Seq2(2) {
+		fmt.Println(i, r.Index, r.Time.Format("15:04"))
+	}
+	// Output:
+	// 3 3 03:00
+	// 4 4 04:00
+}
+
+func ExampleSeq2() {
+	fr, _ := NewRandom("fakeRandom6", 4, 0.5, false)
+	ft, _ := NewTime("fakeTime", time.Date(2020, 2, 7, 0, 0, 0, 0, time.UTC), 60000, 0, 0, false)
+	fp, _ := NewPattern("fakePattern", 2, 1, false)
+	fr.SeekTo(7)
+	ft.SeekTo(7)
+	fp.SeekTo(7)
+
+	for i, v := range fr.Seq2(2) {
+		fmt.Println(i, v)
+	}
+	for i, v := range ft.Seq2(2) {
+		fmt.Println(i, v.Format("15:04"))
+	}
+	for i, v := range fp.Seq2(2) {
+		fmt.Println(i, v)
+	}
+	// Output:
+	// 7 false
+	// 8 false
+	// 7 00:07
+	// 8 00:08
+	// 7 true
+	// 8 false
+}
@@ -0,0 +1,66 @@
+package fake
+
+import (
+	"sync"
+)
+
+// SyncValue wraps a Value so it can be shared between goroutines. None of the
+// types in this package guard their own state: a Pattern, Random, Time or
+// Data may only be used by one goroutine at a time, but different generators
+// never share any state and can run in parallel freely.
+type SyncValue struct {
+	mu sync.Mutex
+	v  Value
+}
+
+// Synchronized wraps a Value so every method call holds a lock.
+func Synchronized(v Value) *SyncValue {
+	return &SyncValue{v: v}
+}
+
+// Next generates the next value.
+func (sv *SyncValue) Next() {
+	sv.mu.Lock()
+	defer sv.mu.Unlock()
+	sv.v.Next()
+}
+
+// Val returns the current value.
+func (sv *SyncValue) Val() interface{} {
+	sv.mu.Lock()
+	defer sv.mu.Unlock()
+	return sv.v.Val()
+}
+
+// Vals returns the next count of values as an interface{} array. No other
+// goroutine can generate values in between.
+func (sv *SyncValue) Vals(count int) []interface{} {
+	sv.mu.Lock()
+	defer sv.mu.Unlock()
+	return sv.v.Vals(count)
+}
+
+// JSONStats retrieves the current stats as s JSON string.
+func (sv *SyncValue) JSONStats() string {
+	sv.mu.Lock()
+	defer sv.mu.Unlock()
+	return sv.v.JSONStats()
+}
+
+// Take returns the current value and generates the next one as a single step
+// so no two goroutines ever get the same value.
+func (sv *SyncValue) Take() interface{} {
+	sv.mu.Lock()
+	defer sv.mu.Unlock()
+	out := sv.v.Val()
+	sv.v.Next()
+	return out
+}
+
+// Do calls fn with the wrapped Value while holding the lock, e.g. to use the
+// typed methods of the wrapped value.
+func (sv *SyncValue) Do(fn func(v Value)) {
+	sv.mu.Lock()
+	defer sv.mu.Unlock()
+	fn(sv.v)
+}
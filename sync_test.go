@@ -0,0 +1,28 @@
+package fake
+
+import (
+	"fmt"
+	"sync"
+)
+
+func ExampleSynchronized() {
+	fp, _ := NewPattern("fakePattern6", 3, 1, true)
+	sv := Synchronized(fp)
+
+	var wg sync.WaitGroup
+	for w := 0; w < 8; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 1000; i++ {
+				sv.Take()
+			}
+		}()
+	}
+	wg.Wait()
+
+	sv.Do(func(v Value) {
+		fmt.Println(v.(*Pattern).Stats.CTotal, v.(*Pattern).Stats.CGoodCount)
+	})
+	// Output: 8001 6001
+}
@@ -0,0 +1,276 @@
+// Package termchart draws fake Data series in the terminal to tune their
+// parameters quickly.
+//
+// A Chart draws every series as a braille line chart with its from/to limits
+// as dotted lines and a marker row for spikes, the onset of the permanent
+// bump and bad samples:
+//
+//  c := termchart.Chart{Width: 30, Height: 5}
+//  c.Series = []termchart.Series{{Data: fakeData1, Gates: []fake.Gate{fakeRandom}}}
+//  err := c.Render(os.Stdout)
+//
+// which may look as follows:
+//
+//  cpu
+//       100 ┤⠁⠁⠁⠁⠁⠁⠁⠁⠁⢁⠥⢥⠁⠁⠁⠁⠁⠁⠁⡥⠥⡁⠁⠁⠁⠁⠁⠁⡡⠥
+//           ┤⠀⠀⠀⠀⠀⠀⠀⠀⠀⠸⠀⠀⡄⠀⠀⠀⠀⠀⠠⠀⠀⠇⠀⠀⠀⠀⡤⠤⠁⠀
+//           ┤⠤⠤⠤⠤⠤⠤⠤⠤⠤⠄⠀⠀⠧⠤⠤⠤⠤⠤⠄⠀⠀⠠⠤⠤⠤⠞⠀⠀⠀⠀
+//           ┤⠀⠀⠀⠀⠀⠀⠀⠀⠀⠀⠀⠀⠀⠀⠀⠀⠀⠀⠀⠀⠀⠀⠀⠀⠀⠀⠀⠀⠀⠀
+//         0 ┤⡀⡀⡀⡀⡀⡀⡀⡀⡀⡀⡀⡀⡀⡀⡀⡀⡀⡀⡀⡀⡀⡀⡀⡀⡀⡀⡀⡀⡀⡀
+//                    x^^^^     x^x^   | ^^^
+//
+// Sparkline draws a compact single line instead.
+package termchart
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	fake "github.com/powerpu/go-fake-ts"
+)
+
+// Series is a single Data drawn by a Chart.
+type Series struct {
+	// The series to draw. Drawing advances it by the samples of the chart.
+	Data *fake.Data
+
+	// Decide whether a sample is "good", bad samples are left out of the
+	// line and marked with an "x". Optional.
+	Gates []fake.Gate
+
+	// The title of the series. Defaults to the ID of the Data.
+	Title string
+}
+
+// Chart draws Data series as braille line charts, one below the other.
+type Chart struct {
+	// The series to draw.
+	Series []Series
+
+	// How many samples of every series to draw. Defaults to the samples of
+	// the Data.
+	Samples int
+
+	// The width of the plot in characters, every character holds 2 samples
+	// side by side. Longer series are squeezed so that every column shows
+	// the lowest to the highest sample it holds. Defaults to 60.
+	Width int
+
+	// The height of the plot in characters, every character holds 4 dots
+	// on top of each other. Defaults to 8.
+	Height int
+}
+
+// Markers of the marker row, shown below the plot.
+const (
+	markSpike     = '^'
+	markPermaBump = '|'
+	markBad       = 'x'
+	markNone      = ' '
+)
+
+// Render draws every series. A legend of the markers is written last.
+func (c *Chart) Render(w io.Writer) error {
+	var sb strings.Builder
+	for _, s := range c.Series {
+		c.render(&sb, s)
+		sb.WriteByte('\n')
+	}
+
+	sb.WriteString(string(markSpike) + " spike  " + string(markPermaBump) + " permanent bump  " + string(markBad) + " bad sample\n")
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// render draws a single series.
+func (c *Chart) render(sb *strings.Builder, s Series) {
+	cfg := s.Data.Config()
+
+	samples := c.Samples
+	if samples <= 0 {
+		samples = int(cfg.Samples)
+	}
+
+	width := c.Width
+	if width <= 0 {
+		width = 60
+	}
+
+	height := c.Height
+	if height <= 0 {
+		height = 8
+	}
+
+	// Collect the samples, NaN marking bad ones
+	values := make([]float64, samples)
+	spiking := make([]bool, samples)
+	onset := int64(-1)
+	if cfg.PermaBumpBy != 0 {
+		onset = cfg.PermaBumpAt
+	}
+
+	start := s.Data.Index()
+	for i := range values {
+		values[i] = s.Data.Float()
+		spiking[i] = s.Data.Spiking()
+		if !fake.AllGood(s.Gates...) {
+			values[i] = math.NaN()
+		}
+
+		s.Data.Next()
+		for _, g := range s.Gates {
+			g.Next()
+		}
+	}
+
+	// The plot always shows the limits
+	lo, hi := math.Min(cfg.From, cfg.To), math.Max(cfg.From, cfg.To)
+	for _, v := range values {
+		if !math.IsNaN(v) {
+			lo, hi = math.Min(lo, v), math.Max(hi, v)
+		}
+	}
+
+	if hi == lo {
+		lo, hi = lo-1, hi+1
+	}
+
+	// One dot column per sample unless there are too many
+	cols := samples
+	if cols > width*2 {
+		cols = width * 2
+	}
+
+	rows := height * 4
+	dots := make([][]bool, rows)
+	for i := range dots {
+		dots[i] = make([]bool, cols)
+	}
+
+	row := func(v float64) int {
+		return int(math.Round((hi - v) / (hi - lo) * float64(rows-1)))
+	}
+
+	// Dotted lines at the limits, every other dot
+	for x := 0; x < cols; x += 2 {
+		dots[row(cfg.From)][x] = true
+		dots[row(cfg.To)][x] = true
+	}
+
+	markers := make([]rune, (cols+1)/2)
+	for i := range markers {
+		markers[i] = markNone
+	}
+
+	for x := 0; x < cols; x++ {
+		from, to := x*samples/cols, (x+1)*samples/cols
+
+		top, bottom := -1, -1
+		for i := from; i < to; i++ {
+			m := &markers[x/2]
+			bad := math.IsNaN(values[i])
+			switch {
+			case start+int64(i) == onset:
+				*m = markPermaBump
+			case bad && *m != markPermaBump:
+				*m = markBad
+			case spiking[i] && *m == markNone:
+				*m = markSpike
+			}
+
+			if bad {
+				continue
+			}
+
+			r := row(values[i])
+			if top < 0 || r < top {
+				top = r
+			}
+
+			if bottom < 0 || r > bottom {
+				bottom = r
+			}
+		}
+
+		for r := top; r >= 0 && r <= bottom; r++ {
+			dots[r][x] = true
+		}
+	}
+
+	title := s.Title
+	if title == "" {
+		title = cfg.ID
+	}
+	sb.WriteString(title + "\n")
+
+	label := func(v float64) string {
+		return strconv.FormatFloat(v, 'g', 4, 64)
+	}
+
+	for y := 0; y < height; y++ {
+		axis := ""
+		switch y {
+		case 0:
+			axis = label(hi)
+		case height - 1:
+			axis = label(lo)
+		}
+
+		fmt.Fprintf(sb, "%8v ┤", axis)
+		for x := 0; x < cols; x += 2 {
+			sb.WriteRune(braille(dots, x, y*4))
+		}
+		sb.WriteByte('\n')
+	}
+
+	sb.WriteString(strings.Repeat(" ", 10) + strings.TrimRight(string(markers), " ") + "\n")
+}
+
+// braille returns the braille character showing the 2 by 4 dots starting at
+// x, y.
+func braille(dots [][]bool, x int, y int) rune {
+	// Bits of the dots, column by column
+	bits := [2][4]rune{{0x01, 0x02, 0x04, 0x40}, {0x08, 0x10, 0x20, 0x80}}
+
+	r := rune(0x2800)
+	for dx := 0; dx < 2; dx++ {
+		for dy := 0; dy < 4; dy++ {
+			if x+dx < len(dots[y+dy]) && dots[y+dy][x+dx] {
+				r |= bits[dx][dy]
+			}
+		}
+	}
+
+	return r
+}
+
+// Sparkline draws values as a single line of block characters scaled from
+// the lowest to the highest value. NaN values are drawn as spaces.
+func Sparkline(values []float64) string {
+	blocks := []rune("▁▂▃▄▅▆▇█")
+
+	lo, hi := math.Inf(1), math.Inf(-1)
+	for _, v := range values {
+		if !math.IsNaN(v) {
+			lo, hi = math.Min(lo, v), math.Max(hi, v)
+		}
+	}
+
+	out := make([]rune, len(values))
+	for i, v := range values {
+		switch {
+		case math.IsNaN(v):
+			out[i] = ' '
+		case hi == lo:
+			out[i] = blocks[len(blocks)/2]
+		default:
+			out[i] = blocks[int(math.Round((v-lo)/(hi-lo)*float64(len(blocks)-1)))]
+		}
+	}
+
+	return string(out)
+}
@@ -0,0 +1,33 @@
+package termchart
+
+import (
+	"fmt"
+	"math"
+	"os"
+
+	fake "github.com/powerpu/go-fake-ts"
+)
+
+func ExampleChart() {
+	cpu, _ := fake.NewDataFromConfig(fake.NewDataConfig("cpu", 120, fake.WithRange(0, 100), fake.WithLimits(true, true), fake.WithSpike(40, 6, 90, 3), fake.WithPermaBump(100, 20, 5)))
+	fr, _ := fake.NewRandom("collector", 4, 0.97, false)
+
+	c := Chart{Width: 30, Height: 5}
+	c.Series = []Series{{Data: cpu, Gates: []fake.Gate{fr}}}
+	c.Render(os.Stdout)
+	// Output:
+	// cpu
+	//      100 ┤⠁⠁⠁⠁⠁⠁⠁⠁⠁⢁⠥⢥⠁⠁⠁⠁⠁⠁⠁⡥⠥⡁⠁⠁⠁⠁⠁⠁⡡⠥
+	//          ┤⠀⠀⠀⠀⠀⠀⠀⠀⠀⠸⠀⠀⡄⠀⠀⠀⠀⠀⠠⠀⠀⠇⠀⠀⠀⠀⡤⠤⠁⠀
+	//          ┤⠤⠤⠤⠤⠤⠤⠤⠤⠤⠄⠀⠀⠧⠤⠤⠤⠤⠤⠄⠀⠀⠠⠤⠤⠤⠞⠀⠀⠀⠀
+	//          ┤⠀⠀⠀⠀⠀⠀⠀⠀⠀⠀⠀⠀⠀⠀⠀⠀⠀⠀⠀⠀⠀⠀⠀⠀⠀⠀⠀⠀⠀⠀
+	//        0 ┤⡀⡀⡀⡀⡀⡀⡀⡀⡀⡀⡀⡀⡀⡀⡀⡀⡀⡀⡀⡀⡀⡀⡀⡀⡀⡀⡀⡀⡀⡀
+	//                   x^^^^     x^x^   | ^^^
+	//
+	// ^ spike  | permanent bump  x bad sample
+}
+
+func ExampleSparkline() {
+	fmt.Println(Sparkline([]float64{1, 2, 3, 4, 5, 6, 7, 8, math.NaN(), 8, 4, 1}))
+	// Output: ▁▂▃▄▅▆▇█ █▄▁
+}
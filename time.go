@@ -3,16 +3,17 @@ package fake
 import (
 	"encoding/json"
 	"errors"
-	"math/rand"
 	"time"
 )
 
 // Time generates timestamp values based on predetermined parameters.
 type Time struct {
 	id           string
+	rnd          source
 	increment    int
 	variance     int
 	direction    int
+	start        time.Time
 	ts           time.Time
 	varianceTime time.Time
 	firstVal     bool
@@ -47,18 +48,23 @@ type TimeStats struct {
 
 // Add adds a value to the running tally.
 func (ts *TimeStats) Add(v interface{}) {
+	ts.add(v.(time.Time))
+}
+
+// add adds a value to the running tally without boxing it.
+func (ts *TimeStats) add(v time.Time) {
 	ts.CTotal++
 	ts.Total++
 
-	ts.CLatest = v.(time.Time)
-	ts.Latest = v.(time.Time)
+	ts.CLatest = v
+	ts.Latest = v
 
 	if ts.CEarliest.IsZero() {
-		ts.CEarliest = v.(time.Time)
+		ts.CEarliest = v
 	}
 
 	if ts.Earliest.IsZero() {
-		ts.Earliest = v.(time.Time)
+		ts.Earliest = v
 	}
 }
 
@@ -75,7 +81,7 @@ func (ts *TimeStats) JSON() string {
 
 // Next generates the next time value.
 func (ft *Time) Next() {
-	a := rand.Float64()
+	a := ft.rnd.Float64()
 
 	// Ensure first time doesn't have any variance to respect the start time parameter
 	if ft.firstVal {
@@ -83,13 +89,22 @@ func (ft *Time) Next() {
 		ft.v = ft.ts
 
 		if ft.keepStats {
-			ft.Stats.Add(ft.v)
+			ft.Stats.add(ft.v)
 		}
 
 		return
 	}
 
 	ft.ts = ft.ts.Add(time.Duration(ft.increment) * time.Millisecond)
+	ft.v = ft.vary(ft.ts, a)
+
+	if ft.keepStats {
+		ft.Stats.add(ft.v)
+	}
+}
+
+// vary applies the variance to a timestamp using the random number a.
+func (ft *Time) vary(ts time.Time, a float64) time.Time {
 	tmp := (float64(ft.variance) * a) - float64(int64(float64(ft.variance)*a))
 	tmp2 := float64(-1)
 
@@ -102,11 +117,42 @@ func (ft *Time) Next() {
 	}
 
 	c := int64(round(float64(ft.variance)*a, 0.0000000005) * tmp2)
-	ft.v = ft.ts.Add(time.Duration(c) * time.Millisecond)
+	return ts.Add(time.Duration(c) * time.Millisecond)
+}
 
-	if ft.keepStats {
-		ft.Stats.Add(ft.v)
+// Index returns the position of the current value, the first sample being 0.
+func (ft *Time) Index() int64 {
+	return int64(ft.rnd.state().N) - 1
+}
+
+// SeekTo jumps to sample n (the first sample being 0) without generating the
+// samples in between. With CounterRandom this takes the same time for any n,
+// otherwise the random numbers in between are still drawn. Only the sample
+// jumped to is added to the statistics.
+func (ft *Time) SeekTo(n int64) {
+	ft.rnd.restore(sourceState{Seed: ft.rnd.state().Seed, N: uint64(n)})
+	ft.firstVal = n == 0
+	ft.ts = ft.start
+
+	if n > 0 {
+		ft.ts = ft.start.Add(time.Duration(n-1) * time.Duration(ft.increment) * time.Millisecond)
+	}
+
+	ft.Next()
+}
+
+// At returns the value of sample n (the first sample being 0) without
+// changing the current value.
+func (ft *Time) At(n int64) time.Time {
+	if n == 0 {
+		return ft.start
 	}
+
+	st := ft.rnd.state()
+	defer ft.rnd.restore(st)
+
+	ft.rnd.restore(sourceState{Seed: st.Seed, N: uint64(n)})
+	return ft.vary(ft.start.Add(time.Duration(n)*time.Duration(ft.increment)*time.Millisecond), ft.rnd.Float64())
 }
 
 // Val returns the current time value as an interface{}
@@ -124,6 +170,49 @@ func (ft *Time) JSONStats() string {
 	return ft.Stats.JSON()
 }
 
+// ID returns the unique id.
+func (ft *Time) ID() string {
+	return ft.id
+}
+
+// timeState is the runtime state of a Time saved by Snapshot().
+type timeState struct {
+	ID       string      `json:"id"`
+	Source   sourceState `json:"source"`
+	TS       time.Time   `json:"ts"`
+	FirstVal bool        `json:"firstVal"`
+	V        time.Time   `json:"v"`
+	Stats    TimeStats   `json:"stats"`
+}
+
+// Snapshot saves the runtime state of the time, including the position of its
+// random numbers and its statistics, so it can be restored later with
+// Restore().
+func (ft *Time) Snapshot() ([]byte, error) {
+	return json.Marshal(timeState{ID: ft.id, Source: ft.rnd.state(), TS: ft.ts, FirstVal: ft.firstVal, V: ft.v, Stats: *ft.Stats})
+}
+
+// Restore restores the runtime state saved by Snapshot(). The time must have
+// been created with the same parameters as the one that was saved and will
+// continue with the identical sequence.
+func (ft *Time) Restore(b []byte) error {
+	var st timeState
+	if err := json.Unmarshal(b, &st); err != nil {
+		return err
+	}
+
+	if st.ID != ft.id {
+		return errors.New("cannot restore a fake time with id '" + ft.id + "' from a snapshot of id '" + st.ID + "'")
+	}
+
+	ft.rnd.restore(st.Source)
+	ft.ts = st.TS
+	ft.firstVal = st.FirstVal
+	ft.v = st.V
+	*ft.Stats = st.Stats
+	return nil
+}
+
 // Time returns the current time value as time.Time
 func (ft *Time) Time() time.Time {
 	return ft.v
@@ -132,13 +221,64 @@ func (ft *Time) Time() time.Time {
 // Times returns the next count of values as a time.Time array.
 func (ft *Time) Times(count int) []time.Time {
 	out := make([]time.Time, count)
+	ft.Fill(out)
+	return out
+}
+
+// Step returns the current value and generates the next one.
+func (ft *Time) Step() time.Time {
+	out := ft.Time()
+	ft.Next()
+	return out
+}
 
-	for i := 0; i < count; i++ {
-		out[i] = ft.Time()
+// Fill writes the next len(dst) values into dst without allocating and
+// returns how many were written.
+func (ft *Time) Fill(dst []time.Time) int {
+	for i := range dst {
+		dst[i] = ft.Time()
 		ft.Next()
 	}
 
-	return out
+	return len(dst)
+}
+
+// TimeConfig holds every parameter of a Time. See NewTime for what each field
+// does.
+type TimeConfig struct {
+	ID        string    `json:"id" yaml:"id"`
+	Start     time.Time `json:"start" yaml:"start"`
+	Increment int       `json:"increment" yaml:"increment"`
+	Variance  int       `json:"variance" yaml:"variance"`
+	Direction int       `json:"direction" yaml:"direction"`
+
+	// A seed to use for the variance. Two times with the same seed (that is
+	// not negative) and parameters generate identical timestamps. A negative
+	// seed uses the current time as the seed.
+	Seed int64 `json:"seed" yaml:"seed"`
+
+	// Use a counter-based random number generator. It generates different
+	// timestamps than the default one for the same seed but lets SeekTo() and
+	// At() jump to any sample instantly.
+	CounterRandom bool `json:"counterRandom" yaml:"counterRandom"`
+
+	KeepStats bool `json:"keepStats" yaml:"keepStats"`
+}
+
+// Validate checks the config for values that would generate nonsense. It
+// returns nil or a *ConfigError listing every invalid field.
+func (cfg TimeConfig) Validate() error {
+	ce := &ConfigError{Kind: "time", ID: cfg.ID}
+
+	if cfg.ID == "" {
+		ce.add("ID", cfg.ID, "cannot be blank")
+	}
+
+	if cfg.Variance < 0 {
+		ce.add("Variance", cfg.Variance, "cannot be less than 0")
+	}
+
+	return ce.err()
 }
 
 // NewTime creates a new fake time. A time has a unique id, an initial first
@@ -146,20 +286,45 @@ func (ft *Time) Times(count int) []time.Time {
 // sample, a direction of the variance (< 0 for always negative, 0 for 50/50 at
 // random, > 0 for always positive) and needs to know wheter to keep internal
 // statistics.
+//
+// The variance always uses a seed of 1 which generates the same timestamps
+// the package-global math/rand source did before Go 1.20. Use
+// NewTimeFromConfig to choose a seed.
 func NewTime(id string, initTs time.Time, increment int, variance int, direction int, keepStats bool) (*Time, error) {
 	if id == "" {
 		return nil, errors.New("ID for a fake time cannot be blank")
 	}
 
+	return NewTimeFromConfig(TimeConfig{
+		ID:        id,
+		Start:     initTs,
+		Increment: increment,
+		Variance:  variance,
+		Direction: direction,
+		Seed:      1,
+		KeepStats: keepStats,
+	})
+}
+
+// NewTimeFromConfig creates a new fake time from a TimeConfig. The config is
+// validated first and a *ConfigError listing every invalid field is returned
+// if it is not valid.
+func NewTimeFromConfig(cfg TimeConfig) (*Time, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
 	t := &Time{
-		id:        id,
-		ts:        initTs,
-		increment: increment,
-		variance:  variance,
-		direction: direction,
+		id:        cfg.ID,
+		rnd:       generateRandom(cfg.Seed, cfg.CounterRandom),
+		start:     cfg.Start,
+		ts:        cfg.Start,
+		increment: cfg.Increment,
+		variance:  cfg.Variance,
+		direction: cfg.Direction,
 		firstVal:  true,
-		keepStats: keepStats,
-		Stats:     &TimeStats{ID: id},
+		keepStats: cfg.KeepStats,
+		Stats:     &TimeStats{ID: cfg.ID},
 	}
 
 	t.Next()
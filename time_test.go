@@ -26,3 +26,52 @@ func ExampleNewTime_b() {
 	}
 	// Output: 1258490098 1258490098 1258490098 1258490098 1258490098 1258490098 1258490098 1258490098 1258490098 1258490098
 }
+
+func ExampleNewTimeFromConfig() {
+	cfg := TimeConfig{
+		ID:        "fakeTime2",
+		Start:     time.Date(2009, 11, 17, 20, 34, 58, 651387237, time.UTC),
+		Increment: 5000,
+		Variance:  1000,
+		Seed:      42,
+	}
+
+	ft1, _ := NewTimeFromConfig(cfg)
+	ft2, _ := NewTimeFromConfig(cfg)
+
+	same := true
+	for _, t := range ft1.Times(1000) {
+		if !t.Equal(ft2.Time()) {
+			same = false
+		}
+		ft2.Next()
+	}
+	fmt.Println(same)
+	// Output: true
+}
+
+func ExampleTime_Snapshot() {
+	t := time.Date(2009, 11, 17, 20, 34, 58, 651387237, time.UTC)
+	ft1, _ := NewTime("fakeTime3", t, 5000, 1000, 0, true)
+	ft1.Times(10)
+
+	snapshot, _ := ft1.Snapshot()
+	ft2, _ := NewTime("fakeTime3", t, 5000, 1000, 0, true)
+	ft2.Restore(snapshot)
+
+	for i := 1; i <= 5; i++ {
+		fmt.Printf("%v ", ft1.Time().Equal(ft2.Time()))
+		ft1.Next()
+		ft2.Next()
+	}
+	// Output: true true true true true
+}
+
+func ExampleTime_SeekTo() {
+	t := time.Date(2009, 11, 17, 20, 34, 58, 651387237, time.UTC)
+	ft, _ := NewTimeFromConfig(TimeConfig{ID: "fakeTime4", Start: t, Increment: 5000, Variance: 1000, Direction: -1, Seed: 1, CounterRandom: true})
+
+	ft.SeekTo(10000000)
+	fmt.Println(ft.Time().Unix(), ft.At(10000001).Unix())
+	// Output: 1308490097 1308490103
+}
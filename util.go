@@ -6,15 +6,114 @@ import (
 	"time"
 )
 
-func generateRandom(seed int64) *rand.Rand {
-	var rnd *rand.Rand
-	if !(seed < 0) {
-		rnd = rand.New(rand.NewSource(seed))
-	} else {
-		rnd = rand.New(rand.NewSource(time.Now().UnixNano()))
+// source generates the random numbers of a generator. Every generator draws
+// exactly one Float64() per sample so the position of a source is also the
+// index of the sample that is drawn next. Sources can be saved with state()
+// and moved to any position with restore().
+type source interface {
+	// Float64 returns the next random number in [0.0,1.0)
+	Float64() float64
+
+	// state returns the current position of the source
+	state() sourceState
+
+	// restore moves the source to the given position
+	restore(st sourceState)
+}
+
+// sourceState is the serializable position of a source.
+type sourceState struct {
+	Seed int64  `json:"seed"`
+	N    uint64 `json:"n"`
+}
+
+// replaySource draws from math/rand and remembers its seed and how many values
+// it has drawn. As math/rand sources cannot be serialized the only way to
+// restore a position is to replay the sequence from the seed, which takes
+// time proportional to the position.
+type replaySource struct {
+	src  rand.Source
+	seed int64
+	n    uint64
+}
+
+// Float64 returns the next random number in [0.0,1.0).
+func (rs *replaySource) Float64() float64 {
+	rs.n++
+	return toFloat64(rs.src.Int63())
+}
+
+func (rs *replaySource) state() sourceState {
+	return sourceState{Seed: rs.seed, N: rs.n}
+}
+
+func (rs *replaySource) restore(st sourceState) {
+	if rs.src == nil || st.Seed != rs.seed || st.N < rs.n {
+		rs.src = rand.NewSource(st.Seed)
+		rs.seed = st.Seed
+		rs.n = 0
+	}
+
+	for ; rs.n < st.N; rs.n++ {
+		rs.src.Int63()
+	}
+}
+
+// counterSource is a counter-based source. The n-th random number is a hash
+// (SplitMix64) of the seed and n so any position can be restored instantly.
+// It generates different numbers than replaySource for the same seed.
+type counterSource struct {
+	seed int64
+	n    uint64
+}
+
+// Float64 returns the next random number in [0.0,1.0).
+func (cs *counterSource) Float64() float64 {
+	cs.n++
+
+	z := uint64(cs.seed) + cs.n*0x9e3779b97f4a7c15
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+	z = z ^ (z >> 31)
+
+	return toFloat64(int64(z >> 1))
+}
+
+func (cs *counterSource) state() sourceState {
+	return sourceState{Seed: cs.seed, N: cs.n}
+}
+
+func (cs *counterSource) restore(st sourceState) {
+	cs.seed = st.Seed
+	cs.n = st.N
+}
+
+// toFloat64 turns a 63 bit random number into a float64 in [0.0,1.0) the same
+// way math/rand does. Unlike math/rand it never draws again when the division
+// rounds up to 1 (which happens with a probability of about 2^-54) so that
+// one sample is always one draw.
+func toFloat64(x int64) float64 {
+	f := float64(x) / (1 << 63)
+	if f == 1 {
+		f = 0
+	}
+
+	return f
+}
+
+func generateRandom(seed int64, counter bool) source {
+	if seed < 0 {
+		seed = time.Now().UnixNano()
+	}
+
+	if counter {
+		return &counterSource{seed: seed}
 	}
 
-	return rnd
+	src := &replaySource{}
+	src.restore(sourceState{Seed: seed})
+
+	return src
 }
 
 func round(x, unit float64) float64 {
@@ -15,6 +15,16 @@ type Value interface {
 	JSONStats() string
 }
 
+// Snapshotter is implemented by fake values whose runtime state can be saved
+// and restored, e.g. to resume generating a large dataset in batches.
+type Snapshotter interface {
+	// Snapshot saves the runtime state
+	Snapshot() ([]byte, error)
+
+	// Restore restores the runtime state saved by Snapshot
+	Restore(b []byte) error
+}
+
 func makeValues(fv Value, count int) []interface{} {
 	out := make([]interface{}, count)
 